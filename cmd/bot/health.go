@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/m4xvel/monetych_bot/internal/config"
+	"github.com/m4xvel/monetych_bot/internal/delivery/httpapi"
+	"github.com/m4xvel/monetych_bot/internal/delivery/telegram"
+	"github.com/m4xvel/monetych_bot/internal/usecase"
+)
+
+const (
+	// A quiet bot makes no Telegram calls for long stretches, so after this
+	// long without one readiness probes the API itself.
+	telegramProbeAfter    = 5 * time.Minute
+	sendQueueBacklogLimit = 1000
+	expertRatingsMaxAge   = 3 * expertRatingsRefreshEvery
+)
+
+func newHealthAPI(
+	pool *pgxpool.Pool,
+	handler *telegram.Handler,
+	gameService *usecase.GameService,
+	expertService *usecase.ExpertService,
+	expertRatingService *usecase.ExpertRatingService,
+) *httpapi.HealthAPI {
+	return httpapi.NewHealthAPI(
+		httpapi.HealthCheck{
+			Name: "database",
+			Check: func(ctx context.Context) (map[string]any, error) {
+				stat := pool.Stat()
+				details := map[string]any{
+					"total_conns":    stat.TotalConns(),
+					"acquired_conns": stat.AcquiredConns(),
+					"idle_conns":     stat.IdleConns(),
+				}
+				return details, pool.Ping(ctx)
+			},
+		},
+		httpapi.HealthCheck{
+			Name: "telegram",
+			Check: func(ctx context.Context) (map[string]any, error) {
+				last := handler.TelegramLastSuccess()
+				if time.Since(last) > telegramProbeAfter {
+					if err := handler.ProbeTelegram(); err != nil {
+						return map[string]any{"last_success": formatTime(last)}, err
+					}
+					last = handler.TelegramLastSuccess()
+				}
+				return map[string]any{"last_success": formatTime(last)}, nil
+			},
+		},
+		// Readiness only: the queue lives in memory, so restarting over a
+		// backlog (usually built up by 429 back-offs) would drop it.
+		httpapi.HealthCheck{
+			Name: "send_queue",
+			Check: func(ctx context.Context) (map[string]any, error) {
+				depth := handler.SendQueueDepth()
+				details := map[string]any{
+					"depth": depth,
+					"limit": sendQueueBacklogLimit,
+				}
+				if depth > sendQueueBacklogLimit {
+					return details, fmt.Errorf("send queue backlog is %d", depth)
+				}
+				return details, nil
+			},
+		},
+		httpapi.HealthCheck{
+			Name: "caches",
+			Check: func(ctx context.Context) (map[string]any, error) {
+				games, _ := gameService.GetAllGames()
+				experts, _ := expertService.GetAllExperts()
+				refreshedAt := expertRatingService.RefreshedAt()
+
+				details := map[string]any{
+					"games":                  len(games),
+					"experts":                len(experts),
+					"expert_ratings_refresh": formatTime(refreshedAt),
+				}
+
+				// A fresh install has no experts yet and ratings fill in
+				// after the first refresh, so only a refresh that stopped
+				// working fails readiness.
+				if !refreshedAt.IsZero() && time.Since(refreshedAt) > expertRatingsMaxAge {
+					return details, errors.New("expert ratings are stale")
+				}
+				return details, nil
+			},
+		},
+	)
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// runHealthcheck backs `bot healthcheck`, the container health command:
+// the runtime image has no shell or curl. It checks liveness only, so a
+// Telegram or database outage doesn't get the container restarted.
+func runHealthcheck() int {
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "healthcheck:", err)
+		return 1
+	}
+
+	if cfg.InternalListenAddr == "" {
+		fmt.Fprintln(os.Stderr, "healthcheck: internal HTTP server is disabled, skipping")
+		return 0
+	}
+
+	host, port, err := net.SplitHostPort(cfg.InternalListenAddr)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "healthcheck:", err)
+		return 1
+	}
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "127.0.0.1"
+	}
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Get("http://" + net.JoinHostPort(host, port) + "/healthz")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "healthcheck:", err)
+		return 1
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		fmt.Fprintln(os.Stderr, "healthcheck: status", resp.StatusCode)
+		return 1
+	}
+	return 0
+}
@@ -6,21 +6,33 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"os"
 	"os/signal"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/m4xvel/monetych_bot/internal/callbackdata"
 	"github.com/m4xvel/monetych_bot/internal/config"
 	"github.com/m4xvel/monetych_bot/internal/crypto"
+	"github.com/m4xvel/monetych_bot/internal/delivery/httpapi"
 	"github.com/m4xvel/monetych_bot/internal/delivery/telegram"
+	"github.com/m4xvel/monetych_bot/internal/fraud"
 	"github.com/m4xvel/monetych_bot/internal/infra"
 	"github.com/m4xvel/monetych_bot/internal/logger"
+	"github.com/m4xvel/monetych_bot/internal/ratelimit"
+	"github.com/m4xvel/monetych_bot/internal/redact"
 	"github.com/m4xvel/monetych_bot/internal/repository/postgres"
 	"github.com/m4xvel/monetych_bot/internal/usecase"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "healthcheck" {
+		os.Exit(runHealthcheck())
+	}
 
 	defer func() {
 		if r := recover(); r != nil {
@@ -53,7 +65,6 @@ func main() {
 	if err != nil {
 		logger.Log.Fatalw("failed to connect to database", "err", err)
 	}
-	defer pool.Close()
 
 	logger.Log.Infow("database connected")
 
@@ -71,29 +82,45 @@ func main() {
 		logger.Log.Fatalw("failed to initialize key crypto", "err", err)
 	}
 
+	signingKey := []byte(cfg.CallbackSigningKey)
+	if len(signingKey) == 0 {
+		signingKey = callbackdata.DeriveKey([]byte(cfg.KeyBase64))
+	}
+	callbackSigner, err := callbackdata.NewSigner(signingKey)
+	if err != nil {
+		logger.Log.Fatalw("failed to initialize callback signer", "err", err)
+	}
+
 	userRepo := postgres.NewUserRepo(pool)
 	stateRepo := postgres.NewUserStateRepo(pool)
 	gameRepo := postgres.NewGameRepo(pool)
 	orderRepo := postgres.NewOrderRepo(pool, keyBase64)
 	expertRepo := postgres.NewExpertRepo(pool)
+	expertRatingRepo := postgres.NewExpertRatingRepo(pool)
 	supportRepo := postgres.NewSupportRepo(pool)
 	orderMessageRepo := postgres.NewOrderMessageRepo(pool)
 	orderChatMessageRepo := postgres.NewOrderChatMessagesRepo(pool, keyBase64)
 	reviewRepo := postgres.NewReviewRepo(pool)
 	callbackTokenRepo := postgres.NewCallbackTokenRepo(pool)
 	userPolicyAcceptancesRepo := postgres.NewUserPolicyAcceptancesRepo(pool)
+	fraudHitRepo := postgres.NewFraudHitRepo(pool)
+	responseTemplateRepo := postgres.NewResponseTemplateRepo(pool)
+	outboxRepo := postgres.NewOutboxRepo(pool, keyBase64)
+	adminAuditRepo := postgres.NewAdminAuditRepo(pool)
 
 	userService := usecase.NewUserService(userRepo)
 	stateService := usecase.NewStateService(stateRepo)
 	gameService := usecase.NewGameService(gameRepo)
 	orderService := usecase.NewOrderService(orderRepo, userRepo)
 	expertService := usecase.NewExpertService(expertRepo)
+	expertRatingService := usecase.NewExpertRatingService(expertRatingRepo)
 	supportService := usecase.NewSupportService(supportRepo)
 	orderMessageService := usecase.NewOrderMessageService(orderMessageRepo)
 	orderChatMessageService := usecase.
 		NewOrderChatMessageService(orderChatMessageRepo)
 	reviewService := usecase.NewReviewService(reviewRepo)
 	callbackTokenService := usecase.NewCallbackTokenService(callbackTokenRepo)
+	adminAuditService := usecase.NewAdminAuditService(adminAuditRepo)
 	userPolicyAcceptancesService := usecase.
 		NewUserPolicyAcceptancesService(
 			userPolicyAcceptancesRepo,
@@ -101,6 +128,21 @@ func main() {
 			cfg.PublicOfferTitle,
 		)
 
+	fraudRules, err := fraud.LoadRules(cfg.FraudRulesFile)
+	if err != nil {
+		logger.Log.Fatalw("failed to load fraud rules", "err", err)
+	}
+	fraudHitService := usecase.NewFraudHitService(
+		fraudHitRepo,
+		fraud.New(cfg.FraudEnabled, fraudRules),
+	)
+
+	responseTemplateService := usecase.NewResponseTemplateService(
+		responseTemplateRepo,
+	)
+
+	outboxService := usecase.NewOutboxService(outboxRepo)
+
 	if err := gameService.InitCache(ctx); err != nil {
 		logger.Log.Fatalw("failed to init game cache", "err", err)
 	}
@@ -115,11 +157,41 @@ func main() {
 
 	logger.Log.Infow("caches initialized")
 
-	go runOrderMessagesCleanup(
-		ctx,
-		orderMessageService,
-		cfg.OrderMsgRetentionDays,
-	)
+	var background sync.WaitGroup
+
+	background.Add(1)
+	go func() {
+		defer background.Done()
+		runOrderMessagesCleanup(
+			ctx,
+			orderMessageService,
+			cfg.OrderMsgRetentionDays,
+		)
+	}()
+
+	redactor := redact.New(redact.Config{
+		Enabled: cfg.RedactionEnabled,
+		Kinds:   redact.ParseKinds(cfg.RedactionRules),
+	})
+
+	var floodGuard *ratelimit.FloodGuard
+	if cfg.FloodEnabled {
+		floodGuard = ratelimit.NewFloodGuard(ratelimit.Config{
+			Limits: map[ratelimit.Role]ratelimit.Limit{
+				ratelimit.RoleUser: {
+					PerMinute: cfg.FloodUserPerMinute,
+					Burst:     cfg.FloodUserBurst,
+				},
+				ratelimit.RoleExpert: {
+					PerMinute: cfg.FloodExpertPerMinute,
+					Burst:     cfg.FloodExpertBurst,
+				},
+			},
+			MuteAfter:    cfg.FloodMuteAfter,
+			MuteFor:      time.Duration(cfg.FloodMuteMinutes) * time.Minute,
+			WarnInterval: 30 * time.Second,
+		})
+	}
 
 	handler := telegram.NewHandler(
 		bot,
@@ -128,25 +200,131 @@ func main() {
 		gameService,
 		orderService,
 		expertService,
+		expertRatingService,
 		supportService,
 		reviewService,
 		orderMessageService,
 		orderChatMessageService,
 		callbackTokenService,
 		userPolicyAcceptancesService,
+		fraudHitService,
+		responseTemplateService,
+		outboxService,
 		cfg.VerificationEnabled,
+		cfg.ReviewNotifyAuthor,
+		cfg.ReviewsChannelID,
+		time.Duration(cfg.OrderPauseGraceHours)*time.Hour,
+		time.Duration(cfg.ReviewReminderHours)*time.Hour,
+		time.Duration(cfg.ReviewAutoPublishMin)*time.Minute,
+		time.Duration(cfg.ExpertHeadStartSec)*time.Second,
+		cfg.ExpertFirstWave,
+		redactor,
+		floodGuard,
+		telegram.NewMemoryDedupStore(
+			time.Duration(cfg.CallbackDedupTTLSec)*time.Second,
+			cfg.CallbackDedupMax,
+		),
+		callbackSigner,
 		cfg.PrivacyPolicyURL,
 		cfg.PublicOfferURL,
 	)
 
-	updates, stopUpdates, err := setupUpdatesSource(ctx, bot, cfg)
+	background.Add(5)
+	go func() {
+		defer background.Done()
+		runPausedOrdersSweeper(ctx, handler)
+	}()
+	go func() {
+		defer background.Done()
+		runReviewScheduler(ctx, handler)
+	}()
+	go func() {
+		defer background.Done()
+		runExpertRatingsRefresher(ctx, expertRatingService)
+	}()
+	go func() {
+		defer background.Done()
+		runCallbackTokensSweeper(ctx, callbackTokenService)
+	}()
+	go func() {
+		defer background.Done()
+		handler.RunOutboxDispatcher(ctx)
+	}()
+
+	// Health and metrics endpoints get their own listener so the public
+	// proxy never reaches them: /readyz may call the Telegram API.
+	internalMux := http.NewServeMux()
+	newHealthAPI(
+		pool,
+		handler,
+		gameService,
+		expertService,
+		expertRatingService,
+	).Register(internalMux)
+	if cfg.MetricsEnabled {
+		registerMetrics(internalMux, handler, orderService)
+	}
+	if cfg.InternalListenAddr != "" {
+		if _, err := startHTTPServer(ctx, cfg.InternalListenAddr, internalMux); err != nil {
+			logger.Log.Fatalw("failed to start internal http server", "err", err)
+		}
+		logger.Log.Infow("internal http server started",
+			"addr", cfg.InternalListenAddr,
+		)
+	}
+
+	mux := http.NewServeMux()
+	if cfg.ReviewsAPIEnabled {
+		httpapi.NewReviewsAPI(reviewService).Register(mux)
+	}
+	if len(cfg.AdminAPITokens) > 0 {
+		httpapi.NewAdminAPI(
+			adminTokens(cfg.AdminAPITokens),
+			orderService,
+			adminAuditService,
+			handler,
+		).Register(mux)
+	}
+
+	updates, stopUpdates, err := setupUpdatesSource(ctx, bot, cfg, mux)
 	if err != nil {
 		logger.Log.Fatalw("failed to configure updates source", "err", err)
 	}
-	defer stopUpdates()
+
+	// Handlers get their own context so a signal does not abort them
+	// halfway through an order transition; it is canceled only once the
+	// shutdown deadline has passed.
+	handlerCtx, cancelHandlers := context.WithCancel(context.Background())
+	defer cancelHandlers()
+
+	dispatcher := telegram.NewUpdateDispatcher(
+		cfg.UpdateWorkers,
+		cfg.UpdateQueueSize,
+		handler.Route,
+	)
+	dispatcher.Start(handlerCtx)
 
 	logger.Log.Infow("bot started, listening for updates")
 
+	receiveUpdates(ctx, updates, dispatcher)
+
+	shutdown(
+		time.Duration(cfg.ShutdownTimeoutSec)*time.Second,
+		stopUpdates,
+		updates,
+		dispatcher,
+		cancelHandlers,
+		handler,
+		&background,
+		pool,
+	)
+}
+
+func receiveUpdates(
+	ctx context.Context,
+	updates tgbotapi.UpdatesChannel,
+	dispatcher *telegram.UpdateDispatcher,
+) {
 	for {
 		select {
 		case <-ctx.Done():
@@ -157,23 +335,93 @@ func main() {
 				logger.Log.Warnw("updates channel closed")
 				return
 			}
-			go func() {
-				defer func() {
-					if r := recover(); r != nil {
-						logger.Log.Errorw("panic in update handler", "panic", r)
-					}
-				}()
-
-				handler.Route(ctx, update)
-			}()
+			dispatcher.Dispatch(ctx, update)
 		}
 	}
 }
 
+func drainUpdates(
+	ctx context.Context,
+	updates tgbotapi.UpdatesChannel,
+	dispatcher *telegram.UpdateDispatcher,
+) int {
+	drained := 0
+	for {
+		select {
+		case update, ok := <-updates:
+			if !ok || !dispatcher.Dispatch(ctx, update) {
+				return drained
+			}
+			drained++
+		default:
+			return drained
+		}
+	}
+}
+
+func shutdown(
+	timeout time.Duration,
+	stopUpdates func(),
+	updates tgbotapi.UpdatesChannel,
+	dispatcher *telegram.UpdateDispatcher,
+	cancelHandlers context.CancelFunc,
+	handler *telegram.Handler,
+	background *sync.WaitGroup,
+	pool *pgxpool.Pool,
+) {
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	logger.Log.Infow("shutdown: stopping update intake")
+	stopUpdates()
+
+	// Updates still buffered were already acknowledged, so Telegram won't
+	// deliver them again.
+	drained := drainUpdates(shutdownCtx, updates, dispatcher)
+	logger.Log.Infow("shutdown: buffered updates dispatched",
+		"count", drained,
+	)
+
+	logger.Log.Infow("shutdown: waiting for running handlers",
+		"timeout", timeout,
+	)
+	if dispatcher.Stop(shutdownCtx) {
+		logger.Log.Infow("shutdown: handlers finished")
+	} else {
+		logger.Log.Warnw("shutdown: handlers did not finish in time, canceling")
+	}
+	cancelHandlers()
+
+	logger.Log.Infow("shutdown: flushing send queue")
+	handler.Shutdown(shutdownCtx)
+
+	logger.Log.Infow("shutdown: stopping background jobs")
+	done := make(chan struct{})
+	go func() {
+		background.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		logger.Log.Infow("shutdown: background jobs stopped")
+	case <-shutdownCtx.Done():
+		logger.Log.Warnw("shutdown: background jobs did not stop in time")
+	}
+
+	logger.Log.Infow("shutdown: closing database pool")
+	pool.Close()
+
+	logger.Log.Infow("shutdown complete")
+}
+
+// setupUpdatesSource starts receiving updates. The HTTP endpoints in mux
+// share the webhook server, or get their own one on HTTP_LISTEN_ADDR when
+// polling.
 func setupUpdatesSource(
 	ctx context.Context,
 	bot *tgbotapi.BotAPI,
 	cfg *config.Config,
+	mux *http.ServeMux,
 ) (tgbotapi.UpdatesChannel, func(), error) {
 	if !cfg.WebhookEnabled {
 		_, err := bot.Request(tgbotapi.DeleteWebhookConfig{
@@ -188,11 +436,24 @@ func setupUpdatesSource(
 		u := tgbotapi.NewUpdate(0)
 		u.Timeout = 60
 
+		stop := bot.StopReceivingUpdates
+		if cfg.HTTPListenAddr != "" {
+			stopServer, err := startHTTPServer(ctx, cfg.HTTPListenAddr, mux)
+			if err != nil {
+				return nil, nil, err
+			}
+			stop = func() {
+				bot.StopReceivingUpdates()
+				stopServer()
+			}
+		}
+
 		logger.Log.Infow("updates source configured",
 			"mode", "polling",
+			"http_listen_addr", cfg.HTTPListenAddr,
 		)
 
-		return bot.GetUpdatesChan(u), bot.StopReceivingUpdates, nil
+		return bot.GetUpdatesChan(u), stop, nil
 	}
 
 	webhookConfig, err := tgbotapi.NewWebhook(cfg.WebhookURL)
@@ -208,20 +469,46 @@ func setupUpdatesSource(
 		return nil, nil, errors.New("TELEGRAM_WEBHOOK_URL must include a non-root path")
 	}
 
-	webhookConfig.DropPendingUpdates = cfg.WebhookDropPending
+	// The library's WebhookConfig predates secret_token, so the request is
+	// built by hand.
+	params := tgbotapi.Params{"url": webhookConfig.URL.String()}
+	params.AddBool("drop_pending_updates", cfg.WebhookDropPending)
+	params.AddNonEmpty("secret_token", cfg.WebhookSecret)
 
-	if _, err := bot.Request(webhookConfig); err != nil {
+	if _, err := bot.MakeRequest("setWebhook", params); err != nil {
 		return nil, nil, fmt.Errorf("failed to set telegram webhook: %w", err)
 	}
 
-	updates := bot.ListenForWebhook(webhookConfig.URL.Path)
+	webhook := httpapi.NewWebhookAPI(bot, webhookConfig.URL.Path, cfg.WebhookSecret)
+	webhook.Register(mux)
 
-	listener, err := net.Listen("tcp", cfg.WebhookListenAddr)
+	stop, err := startHTTPServer(ctx, cfg.WebhookListenAddr, mux)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to listen for webhook on %s: %w", cfg.WebhookListenAddr, err)
+		return nil, nil, err
+	}
+
+	logger.Log.Infow("updates source configured",
+		"mode", "webhook",
+		"webhook_url", cfg.WebhookURL,
+		"listen_addr", cfg.WebhookListenAddr,
+		"path", webhookConfig.URL.Path,
+	)
+
+	return webhook.Updates(), stop, nil
+}
+
+func startHTTPServer(
+	ctx context.Context,
+	addr string,
+	handler http.Handler,
+) (func(), error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for http on %s: %w", addr, err)
 	}
 
 	server := &http.Server{
+		Handler:           handler,
 		ReadHeaderTimeout: 5 * time.Second,
 	}
 
@@ -239,37 +526,33 @@ func setupUpdatesSource(
 			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 			defer cancel()
 			if err := server.Shutdown(shutdownCtx); err != nil {
-				logger.Log.Errorw("failed to shutdown webhook server",
+				logger.Log.Errorw("failed to shutdown http server",
+					"addr", addr,
 					"err", err,
 				)
 			}
 		case err := <-serverErr:
 			if err != nil {
-				logger.Log.Errorw("webhook server stopped unexpectedly",
+				logger.Log.Errorw("http server stopped unexpectedly",
+					"addr", addr,
 					"err", err,
 				)
 			}
 		}
 	}()
 
-	logger.Log.Infow("updates source configured",
-		"mode", "webhook",
-		"webhook_url", cfg.WebhookURL,
-		"listen_addr", cfg.WebhookListenAddr,
-		"path", webhookConfig.URL.Path,
-	)
-
 	stop := func() {
 		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 		if err := server.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
-			logger.Log.Errorw("failed to stop webhook server",
+			logger.Log.Errorw("failed to stop http server",
+				"addr", addr,
 				"err", err,
 			)
 		}
 	}
 
-	return updates, stop, nil
+	return stop, nil
 }
 
 func runOrderMessagesCleanup(
@@ -321,3 +604,136 @@ func runOrderMessagesCleanup(
 		}
 	}
 }
+
+func runPausedOrdersSweeper(
+	ctx context.Context,
+	handler *telegram.Handler,
+) {
+	run := func() {
+		sweepCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+
+		handler.CancelStalePausedOrders(sweepCtx)
+	}
+
+	run()
+
+	ticker := time.NewTicker(15 * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			run()
+		}
+	}
+}
+
+func runReviewScheduler(
+	ctx context.Context,
+	handler *telegram.Handler,
+) {
+	run := func() {
+		runCtx, cancel := context.WithTimeout(ctx, time.Minute)
+		defer cancel()
+
+		handler.PublishStaleReviews(runCtx)
+		handler.RemindUnratedOrders(runCtx)
+	}
+
+	run()
+
+	ticker := time.NewTicker(5 * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			run()
+		}
+	}
+}
+
+func runCallbackTokensSweeper(
+	ctx context.Context,
+	service *usecase.CallbackTokenService,
+) {
+	run := func() {
+		sweepCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+
+		deletedRows, err := service.DeleteExpired(sweepCtx)
+		if err != nil {
+			logger.Log.Errorw("callback tokens cleanup failed",
+				"err", err,
+			)
+			return
+		}
+
+		if deletedRows > 0 {
+			logger.Log.Infow("expired callback tokens cleaned",
+				"deleted_rows", deletedRows,
+			)
+		}
+	}
+
+	run()
+
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			run()
+		}
+	}
+}
+
+const expertRatingsRefreshEvery = 15 * time.Minute
+
+func runExpertRatingsRefresher(
+	ctx context.Context,
+	service *usecase.ExpertRatingService,
+) {
+	run := func() {
+		refreshCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+
+		if err := service.Refresh(refreshCtx); err != nil {
+			logger.Log.Errorw("expert ratings refresh failed",
+				"err", err,
+			)
+		}
+	}
+
+	run()
+
+	ticker := time.NewTicker(expertRatingsRefreshEvery)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			run()
+		}
+	}
+}
+
+// adminTokens splits the validated name:token pairs from ADMIN_API_TOKENS.
+func adminTokens(entries []string) []httpapi.AdminToken {
+	tokens := make([]httpapi.AdminToken, 0, len(entries))
+	for _, entry := range entries {
+		name, token, _ := strings.Cut(entry, ":")
+		tokens = append(tokens, httpapi.AdminToken{Name: name, Token: token})
+	}
+	return tokens
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/m4xvel/monetych_bot/internal/delivery/telegram"
+	"github.com/m4xvel/monetych_bot/internal/metrics"
+	"github.com/m4xvel/monetych_bot/internal/usecase"
+)
+
+func registerMetrics(
+	mux *http.ServeMux,
+	handler *telegram.Handler,
+	orderService *usecase.OrderService,
+) {
+	metrics.NewGaugeFunc(
+		"bot_send_queue_depth",
+		"Telegram requests waiting in the send queue.",
+		func() float64 { return float64(handler.SendQueueDepth()) },
+	)
+	metrics.NewGaugeVecFunc(
+		"bot_expert_active_orders",
+		"Accepted and confirmed orders per active expert.",
+		[]string{"expert_id"},
+		func(ctx context.Context) ([]metrics.Sample, error) {
+			counts, err := orderService.CountActiveByExpert(ctx)
+			if err != nil {
+				return nil, err
+			}
+			samples := make([]metrics.Sample, 0, len(counts))
+			for expertID, count := range counts {
+				samples = append(samples, metrics.Sample{
+					Labels: []string{strconv.Itoa(expertID)},
+					Value:  float64(count),
+				})
+			}
+			return samples, nil
+		},
+	)
+
+	mux.Handle("GET /metrics", metrics.Default.Handler())
+}
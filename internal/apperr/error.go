@@ -10,6 +10,7 @@ const (
 	KindInvalid      Kind = "invalid"
 	KindUnauthorized Kind = "unauthorized"
 	KindForbidden    Kind = "forbidden"
+	KindExpired      Kind = "expired"
 	KindRateLimited  Kind = "rate_limited"
 	KindUnavailable  Kind = "unavailable"
 	KindExternal     Kind = "external"
@@ -63,6 +64,7 @@ var (
 	ErrInvalid      = &Error{Kind: KindInvalid}
 	ErrUnauthorized = &Error{Kind: KindUnauthorized}
 	ErrForbidden    = &Error{Kind: KindForbidden}
+	ErrExpired      = &Error{Kind: KindExpired}
 	ErrRateLimited  = &Error{Kind: KindRateLimited}
 	ErrUnavailable  = &Error{Kind: KindUnavailable}
 	ErrExternal     = &Error{Kind: KindExternal}
@@ -8,6 +8,12 @@ import (
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
+var botBlockedMarkers = []string{
+	"bot was blocked by the user",
+	"user is deactivated",
+	"bot can't initiate conversation with a user",
+}
+
 type TelegramError struct {
 	Op         string
 	Code       int
@@ -81,6 +87,21 @@ func (e *TelegramError) Kind() Kind {
 	}
 }
 
+func (e *TelegramError) IsBotBlocked() bool {
+	if e == nil || e.Code != 403 {
+		return false
+	}
+
+	msg := strings.ToLower(e.Message)
+	for _, marker := range botBlockedMarkers {
+		if strings.Contains(msg, marker) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (e *TelegramError) Is(target error) bool {
 	switch t := target.(type) {
 	case *Error:
@@ -120,3 +141,18 @@ func WrapTelegram(op string, err error) error {
 		Err: err,
 	}
 }
+
+func IsBotBlocked(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var tgErr *TelegramError
+	if !errors.As(err, &tgErr) {
+		if !errors.As(WrapTelegram("", err), &tgErr) {
+			return false
+		}
+	}
+
+	return tgErr.IsBotBlocked()
+}
@@ -0,0 +1,107 @@
+package callbackdata
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// MaxLen is the Telegram limit for inline button callback data.
+const MaxLen = 64
+
+const macSize = 10
+
+var (
+	ErrInvalid = errors.New("callback data is invalid")
+	ErrExpired = errors.New("callback data is expired")
+)
+
+// Signer packs integer payloads into compact HMAC-signed callback data, so
+// navigation buttons need no server-side state.
+type Signer struct {
+	key []byte
+}
+
+func NewSigner(key []byte) (*Signer, error) {
+	if len(key) < 16 {
+		return nil, errors.New("callback signing key must be at least 16 bytes")
+	}
+	return &Signer{key: key}, nil
+}
+
+// DeriveKey produces a signing key from another secret, so deployments
+// without a dedicated key do not reuse it verbatim.
+func DeriveKey(secret []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte("callback-data"))
+	return mac.Sum(nil)
+}
+
+// Sign returns the token part of "<action>:<token>".
+func (s *Signer) Sign(
+	action string,
+	fields []int64,
+	expiresAt time.Time,
+) (string, error) {
+	body := binary.AppendUvarint(nil, uint64(expiresAt.Unix()))
+	for _, f := range fields {
+		body = binary.AppendVarint(body, f)
+	}
+	body = append(body, s.mac(action, body)...)
+
+	token := base64.RawURLEncoding.EncodeToString(body)
+	if len(action)+1+len(token) > MaxLen {
+		return "", fmt.Errorf("callback data for %q exceeds %d bytes", action, MaxLen)
+	}
+	return token, nil
+}
+
+// Verify checks the signature and expiry and returns the signed fields.
+func (s *Signer) Verify(
+	action string,
+	token string,
+	now time.Time,
+) ([]int64, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(raw) <= macSize {
+		return nil, ErrInvalid
+	}
+
+	body, sum := raw[:len(raw)-macSize], raw[len(raw)-macSize:]
+	if !hmac.Equal(sum, s.mac(action, body)) {
+		return nil, ErrInvalid
+	}
+
+	expiresAt, read := binary.Uvarint(body)
+	if read <= 0 {
+		return nil, ErrInvalid
+	}
+	body = body[read:]
+
+	var fields []int64
+	for len(body) > 0 {
+		f, read := binary.Varint(body)
+		if read <= 0 {
+			return nil, ErrInvalid
+		}
+		fields = append(fields, f)
+		body = body[read:]
+	}
+	if now.Unix() > int64(expiresAt) {
+		return nil, ErrExpired
+	}
+
+	return fields, nil
+}
+
+func (s *Signer) mac(action string, body []byte) []byte {
+	m := hmac.New(sha256.New, s.key)
+	m.Write([]byte(action))
+	m.Write([]byte{0})
+	m.Write(body)
+	return m.Sum(nil)[:macSize]
+}
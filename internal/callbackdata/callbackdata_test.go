@@ -0,0 +1,140 @@
+package callbackdata
+
+import (
+	"encoding/base64"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testKey = []byte("0123456789abcdef0123456789abcdef")
+
+func newTestSigner(t *testing.T, key []byte) *Signer {
+	t.Helper()
+
+	s, err := NewSigner(key)
+	if err != nil {
+		t.Fatalf("NewSigner: %v", err)
+	}
+	return s
+}
+
+func TestSignVerifyRoundTrip(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	s := newTestSigner(t, testKey)
+
+	tests := []struct {
+		name   string
+		action string
+		fields []int64
+	}{
+		{name: "no fields", action: "game", fields: nil},
+		{name: "small ids", action: "type", fields: []int64{42, 1, 2}},
+		{name: "group chat id", action: "game", fields: []int64{-1001234567890, 7}},
+		{name: "page numbers", action: "reviews", fields: []int64{5_000_000_000, 0, 12}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := s.Sign(tt.action, tt.fields, now.Add(time.Hour))
+			if err != nil {
+				t.Fatalf("Sign: %v", err)
+			}
+			if n := len(tt.action) + 1 + len(token); n > MaxLen {
+				t.Fatalf("callback data is %d bytes, limit %d", n, MaxLen)
+			}
+
+			got, err := s.Verify(tt.action, token, now)
+			if err != nil {
+				t.Fatalf("Verify: %v", err)
+			}
+			if !slices.Equal(got, tt.fields) {
+				t.Fatalf("fields = %v, want %v", got, tt.fields)
+			}
+		})
+	}
+}
+
+func TestVerifyRejects(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	s := newTestSigner(t, testKey)
+
+	token, err := s.Sign("game", []int64{42, 7}, now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		t.Fatalf("decode token: %v", err)
+	}
+
+	flip := func(i int) string {
+		b := slices.Clone(raw)
+		b[i] ^= 0x01
+		return base64.RawURLEncoding.EncodeToString(b)
+	}
+
+	expired, err := s.Sign("game", []int64{42, 7}, now.Add(-time.Second))
+	if err != nil {
+		t.Fatalf("Sign expired: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		signer *Signer
+		action string
+		token  string
+		want   error
+	}{
+		{name: "tampered mac", signer: s, action: "game", token: flip(len(raw) - 1), want: ErrInvalid},
+		{name: "tampered body", signer: s, action: "game", token: flip(len(raw) - macSize - 1), want: ErrInvalid},
+		{name: "wrong key", signer: newTestSigner(t, []byte("fedcba9876543210fedcba9876543210")), action: "game", token: token, want: ErrInvalid},
+		{name: "other action", signer: s, action: "type", token: token, want: ErrInvalid},
+		{name: "truncated", signer: s, action: "game", token: token[:8], want: ErrInvalid},
+		{name: "not base64", signer: s, action: "game", token: "!!!", want: ErrInvalid},
+		{name: "expired", signer: s, action: "game", token: expired, want: ErrExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields, err := tt.signer.Verify(tt.action, tt.token, now)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Verify error = %v, want %v", err, tt.want)
+			}
+			if fields != nil {
+				t.Fatalf("fields = %v, want nil", fields)
+			}
+		})
+	}
+}
+
+func TestSignRejectsOversizePayload(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	s := newTestSigner(t, testKey)
+
+	tests := []struct {
+		name   string
+		action string
+		fields []int64
+	}{
+		{name: "too many fields", action: "game", fields: slices.Repeat([]int64{-1001234567890}, 6)},
+		{name: "long action", action: strings.Repeat("a", MaxLen), fields: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := s.Sign(tt.action, tt.fields, now.Add(time.Hour))
+			if err == nil {
+				t.Fatalf("Sign returned %q, want an error", token)
+			}
+		})
+	}
+}
+
+func TestNewSignerRejectsShortKey(t *testing.T) {
+	if _, err := NewSigner([]byte("short")); err == nil {
+		t.Fatal("NewSigner accepted a 5-byte key")
+	}
+}
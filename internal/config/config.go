@@ -4,10 +4,13 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
 
+const minAdminTokenLength = 32
+
 type Config struct {
 	Env                   string
 	BotToken              string
@@ -15,15 +18,45 @@ type Config struct {
 	KeyBase64             string
 	Debug                 bool
 	VerificationEnabled   bool
+	ReviewNotifyAuthor    bool
+	ReviewsChannelID      int64
 	PrivacyPolicyURL      string
 	PublicOfferURL        string
 	PrivacyPolicyTitle    string
 	PublicOfferTitle      string
 	OrderMsgRetentionDays int
+	OrderPauseGraceHours  int
+	ReviewReminderHours   int
+	ReviewAutoPublishMin  int
+	ExpertHeadStartSec    int
+	ExpertFirstWave       int
+	RedactionEnabled      bool
+	RedactionRules        []string
+	FraudEnabled          bool
+	FraudRulesFile        string
+	FloodEnabled          bool
+	FloodUserPerMinute    int
+	FloodUserBurst        int
+	FloodExpertPerMinute  int
+	FloodExpertBurst      int
+	FloodMuteAfter        int
+	FloodMuteMinutes      int
+	UpdateWorkers         int
+	UpdateQueueSize       int
+	ShutdownTimeoutSec    int
+	CallbackDedupTTLSec   int
+	CallbackDedupMax      int
+	CallbackSigningKey    string
 	WebhookEnabled        bool
 	WebhookURL            string
 	WebhookListenAddr     string
 	WebhookDropPending    bool
+	WebhookSecret         string
+	HTTPListenAddr        string
+	InternalListenAddr    string
+	ReviewsAPIEnabled     bool
+	MetricsEnabled        bool
+	AdminAPITokens        []string
 }
 
 func Load() (*Config, error) {
@@ -36,15 +69,45 @@ func Load() (*Config, error) {
 		KeyBase64:             os.Getenv("CHAT_CRYPTO_KEY"),
 		Debug:                 os.Getenv("DEBUG") == "true",
 		VerificationEnabled:   getEnvBool("ENABLE_VERIFICATION", true),
+		ReviewNotifyAuthor:    getEnvBool("REVIEW_MODERATION_NOTIFY_AUTHOR", true),
+		ReviewsChannelID:      getEnvInt64("REVIEWS_CHANNEL_ID", 0),
 		PrivacyPolicyURL:      os.Getenv("PRIVACY_POLICY_URL"),
 		PublicOfferURL:        os.Getenv("PUBLIC_OFFER_URL"),
 		PrivacyPolicyTitle:    getEnv("PRIVACY_POLICY_TITLE", "Политика конфиденциальности"),
 		PublicOfferTitle:      getEnv("PUBLIC_OFFER_TITLE", "Публичная оферта"),
 		OrderMsgRetentionDays: getEnvInt("ORDER_MESSAGES_RETENTION_DAYS", 30),
+		OrderPauseGraceHours:  getEnvInt("ORDER_PAUSE_GRACE_HOURS", 24),
+		ReviewReminderHours:   getEnvInt("REVIEW_REMINDER_DELAY_HOURS", 24),
+		ReviewAutoPublishMin:  getEnvInt("REVIEW_AUTO_PUBLISH_MINUTES", 60),
+		ExpertHeadStartSec:    getEnvInt("EXPERT_HEAD_START_SECONDS", 60),
+		ExpertFirstWave:       getEnvInt("EXPERT_FIRST_WAVE_SIZE", 3),
+		RedactionEnabled:      getEnvBool("REDACTION_ENABLED", true),
+		RedactionRules:        getEnvList("REDACTION_RULES", []string{"card", "phone", "email"}),
+		FraudEnabled:          getEnvBool("FRAUD_DETECTION_ENABLED", true),
+		FraudRulesFile:        os.Getenv("FRAUD_RULES_FILE"),
+		FloodEnabled:          getEnvBool("FLOOD_PROTECTION_ENABLED", true),
+		FloodUserPerMinute:    getEnvInt("FLOOD_USER_PER_MINUTE", 20),
+		FloodUserBurst:        getEnvInt("FLOOD_USER_BURST", 10),
+		FloodExpertPerMinute:  getEnvInt("FLOOD_EXPERT_PER_MINUTE", 60),
+		FloodExpertBurst:      getEnvInt("FLOOD_EXPERT_BURST", 30),
+		FloodMuteAfter:        getEnvInt("FLOOD_MUTE_AFTER", 5),
+		FloodMuteMinutes:      getEnvInt("FLOOD_MUTE_MINUTES", 10),
+		UpdateWorkers:         getEnvInt("UPDATE_WORKERS", 16),
+		UpdateQueueSize:       getEnvInt("UPDATE_QUEUE_SIZE", 100),
+		ShutdownTimeoutSec:    getEnvInt("SHUTDOWN_TIMEOUT_SECONDS", 20),
+		CallbackDedupTTLSec:   getEnvInt("CALLBACK_DEDUP_TTL_SECONDS", 60),
+		CallbackDedupMax:      getEnvInt("CALLBACK_DEDUP_MAX_ENTRIES", 10000),
+		CallbackSigningKey:    os.Getenv("CALLBACK_SIGNING_KEY"),
 		WebhookEnabled:        getEnvBool("TELEGRAM_WEBHOOK_ENABLED", getEnv("APP_ENV", "dev") == "prod"),
 		WebhookURL:            os.Getenv("TELEGRAM_WEBHOOK_URL"),
 		WebhookListenAddr:     getEnv("TELEGRAM_WEBHOOK_LISTEN_ADDR", ":8080"),
 		WebhookDropPending:    getEnvBool("TELEGRAM_WEBHOOK_DROP_PENDING_UPDATES", false),
+		WebhookSecret:         os.Getenv("TELEGRAM_WEBHOOK_SECRET"),
+		HTTPListenAddr:        os.Getenv("HTTP_LISTEN_ADDR"),
+		InternalListenAddr:    getEnvOrEmpty("INTERNAL_LISTEN_ADDR", ":9091"),
+		ReviewsAPIEnabled:     getEnvBool("REVIEWS_API_ENABLED", true),
+		MetricsEnabled:        getEnvBool("METRICS_ENABLED", true),
+		AdminAPITokens:        getEnvList("ADMIN_API_TOKENS", nil),
 	}
 
 	if err := cfg.validate(); err != nil {
@@ -83,6 +146,60 @@ func (c *Config) validate() error {
 		return fmt.Errorf("PUBLIC_OFFER_TITLE is not set")
 	}
 
+	if c.OrderPauseGraceHours <= 0 {
+		return fmt.Errorf("invalid ORDER_PAUSE_GRACE_HOURS: %d", c.OrderPauseGraceHours)
+	}
+
+	if c.ReviewReminderHours < 0 {
+		return fmt.Errorf("invalid REVIEW_REMINDER_DELAY_HOURS: %d", c.ReviewReminderHours)
+	}
+
+	if c.ReviewAutoPublishMin < 0 {
+		return fmt.Errorf("invalid REVIEW_AUTO_PUBLISH_MINUTES: %d", c.ReviewAutoPublishMin)
+	}
+
+	if c.ExpertHeadStartSec < 0 {
+		return fmt.Errorf("invalid EXPERT_HEAD_START_SECONDS: %d", c.ExpertHeadStartSec)
+	}
+
+	if c.ExpertFirstWave < 0 {
+		return fmt.Errorf("invalid EXPERT_FIRST_WAVE_SIZE: %d", c.ExpertFirstWave)
+	}
+
+	if c.FloodEnabled {
+		if c.FloodUserPerMinute <= 0 || c.FloodUserBurst <= 0 {
+			return fmt.Errorf("invalid FLOOD_USER_PER_MINUTE/FLOOD_USER_BURST: %d/%d",
+				c.FloodUserPerMinute, c.FloodUserBurst)
+		}
+		if c.FloodExpertPerMinute <= 0 || c.FloodExpertBurst <= 0 {
+			return fmt.Errorf("invalid FLOOD_EXPERT_PER_MINUTE/FLOOD_EXPERT_BURST: %d/%d",
+				c.FloodExpertPerMinute, c.FloodExpertBurst)
+		}
+		if c.FloodMuteMinutes <= 0 {
+			return fmt.Errorf("invalid FLOOD_MUTE_MINUTES: %d", c.FloodMuteMinutes)
+		}
+	}
+
+	if c.UpdateWorkers <= 0 {
+		return fmt.Errorf("invalid UPDATE_WORKERS: %d", c.UpdateWorkers)
+	}
+
+	if c.UpdateQueueSize <= 0 {
+		return fmt.Errorf("invalid UPDATE_QUEUE_SIZE: %d", c.UpdateQueueSize)
+	}
+
+	if c.ShutdownTimeoutSec <= 0 {
+		return fmt.Errorf("invalid SHUTDOWN_TIMEOUT_SECONDS: %d", c.ShutdownTimeoutSec)
+	}
+
+	if c.CallbackDedupTTLSec <= 0 {
+		return fmt.Errorf("invalid CALLBACK_DEDUP_TTL_SECONDS: %d", c.CallbackDedupTTLSec)
+	}
+
+	if c.CallbackDedupMax <= 0 {
+		return fmt.Errorf("invalid CALLBACK_DEDUP_MAX_ENTRIES: %d", c.CallbackDedupMax)
+	}
+
 	if c.Env != "dev" && c.Env != "prod" {
 		return fmt.Errorf("invalid APP_ENV: %s", c.Env)
 	}
@@ -91,9 +208,52 @@ func (c *Config) validate() error {
 		return fmt.Errorf("TELEGRAM_WEBHOOK_URL is not set while TELEGRAM_WEBHOOK_ENABLED=true")
 	}
 
+	publicAddr := c.HTTPListenAddr
+	if c.WebhookEnabled {
+		publicAddr = c.WebhookListenAddr
+	}
+	if c.InternalListenAddr != "" && c.InternalListenAddr == publicAddr {
+		return fmt.Errorf("INTERNAL_LISTEN_ADDR must differ from the public listen address %s", publicAddr)
+	}
+
+	names := make(map[string]bool, len(c.AdminAPITokens))
+	for _, entry := range c.AdminAPITokens {
+		name, token, ok := strings.Cut(entry, ":")
+		if !ok || name == "" || len(token) < minAdminTokenLength {
+			return fmt.Errorf("invalid ADMIN_API_TOKENS entry %q: want name:token with a token of at least %d characters",
+				name, minAdminTokenLength)
+		}
+		if names[name] {
+			return fmt.Errorf("duplicate ADMIN_API_TOKENS name %q", name)
+		}
+		names[name] = true
+	}
+
+	if c.WebhookEnabled && !validWebhookSecret(c.WebhookSecret) {
+		return fmt.Errorf("TELEGRAM_WEBHOOK_SECRET must be 1-256 characters of A-Z, a-z, 0-9, _ and -")
+	}
+
 	return nil
 }
 
+// validWebhookSecret checks the charset Telegram accepts for secret_token.
+func validWebhookSecret(s string) bool {
+	if len(s) == 0 || len(s) > 256 {
+		return false
+	}
+	for _, c := range s {
+		switch {
+		case c >= 'A' && c <= 'Z',
+			c >= 'a' && c <= 'z',
+			c >= '0' && c <= '9',
+			c == '_', c == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
 func getEnv(key, defaultValue string) string {
 	if v := os.Getenv(key); v != "" {
 		return v
@@ -101,6 +261,14 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// getEnvOrEmpty is getEnv for settings an explicitly empty value disables.
+func getEnvOrEmpty(key, defaultValue string) string {
+	if v, ok := os.LookupEnv(key); ok {
+		return v
+	}
+	return defaultValue
+}
+
 func getEnvInt(key string, defaultValue int) int {
 	v := os.Getenv(key)
 	if v == "" {
@@ -115,6 +283,20 @@ func getEnvInt(key string, defaultValue int) int {
 	return n
 }
 
+func getEnvInt64(key string, defaultValue int64) int64 {
+	v := os.Getenv(key)
+	if v == "" {
+		return defaultValue
+	}
+
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return defaultValue
+	}
+
+	return n
+}
+
 func getEnvBool(key string, defaultValue bool) bool {
 	v := os.Getenv(key)
 	if v == "" {
@@ -130,3 +312,20 @@ func getEnvBool(key string, defaultValue bool) bool {
 		return defaultValue
 	}
 }
+
+func getEnvList(key string, defaultValue []string) []string {
+	v := os.Getenv(key)
+	if v == "" {
+		return defaultValue
+	}
+
+	parts := strings.Split(v, ",")
+	out := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+
+	return out
+}
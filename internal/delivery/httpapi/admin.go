@@ -0,0 +1,512 @@
+package httpapi
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/m4xvel/monetych_bot/internal/apperr"
+	"github.com/m4xvel/monetych_bot/internal/domain"
+	"github.com/m4xvel/monetych_bot/internal/logger"
+	"github.com/m4xvel/monetych_bot/internal/usecase"
+)
+
+const (
+	adminDefaultPerPage = 50
+	adminMaxPerPage     = 200
+	adminAuditTimeout   = 5 * time.Second
+	adminDateLayout     = "2006-01-02"
+	adminAnonymousActor = "anonymous"
+)
+
+// AdminToken is a bearer token for the admin API. Name identifies the
+// holder in the audit log.
+type AdminToken struct {
+	Name  string
+	Token string
+}
+
+// OrderTransitioner changes an order status together with whatever the bot
+// has to tell the people involved.
+type OrderTransitioner interface {
+	TransitionOrder(ctx context.Context, orderID int, to domain.OrderStatus) error
+}
+
+// AdminAPI lets support inspect and manage orders outside Telegram. Every
+// request is written to the audit log, rejected ones included. Authenticated
+// requests are recorded before they run and refused when that fails.
+type AdminAPI struct {
+	tokens       []adminToken
+	orderService *usecase.OrderService
+	auditService *usecase.AdminAuditService
+	transitioner OrderTransitioner
+}
+
+type adminToken struct {
+	name string
+	hash [sha256.Size]byte
+}
+
+func NewAdminAPI(
+	tokens []AdminToken,
+	os *usecase.OrderService,
+	as *usecase.AdminAuditService,
+	transitioner OrderTransitioner,
+) *AdminAPI {
+	hashed := make([]adminToken, 0, len(tokens))
+	for _, t := range tokens {
+		hashed = append(hashed, adminToken{
+			name: t.Name,
+			hash: sha256.Sum256([]byte(t.Token)),
+		})
+	}
+
+	return &AdminAPI{
+		tokens:       hashed,
+		orderService: os,
+		auditService: as,
+		transitioner: transitioner,
+	}
+}
+
+func (a *AdminAPI) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/admin/orders", a.handle("orders.list", a.handleList))
+	mux.HandleFunc("GET /api/admin/orders/{id}/transcript", a.handle("orders.transcript", a.handleTranscript))
+	mux.HandleFunc("POST /api/admin/orders/{id}/status", a.handle("orders.transition", a.handleTransition))
+}
+
+// adminRequest collects what a handler wants in its audit entry.
+type adminRequest struct {
+	actor   string
+	orderID *int
+	details map[string]any
+}
+
+type adminHandlerFunc func(w http.ResponseWriter, r *http.Request, req *adminRequest)
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (rec *statusRecorder) WriteHeader(status int) {
+	rec.status = status
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (a *AdminAPI) handle(action string, fn adminHandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		actor, ok := a.authenticate(r)
+		if !ok {
+			logger.Log.Warnw("admin api request with invalid token rejected",
+				"action", action,
+				"remote_ip", remoteIP(r),
+				"forwarded_for", r.Header.Get("X-Forwarded-For"),
+			)
+			a.auditRejected(r, action)
+			w.Header().Set("WWW-Authenticate", `Bearer realm="admin"`)
+			writeError(w, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+
+		req := &adminRequest{
+			actor:   actor,
+			details: make(map[string]any),
+		}
+		if q := r.URL.RawQuery; q != "" {
+			req.details["query"] = q
+		}
+		if id, err := strconv.Atoi(r.PathValue("id")); err == nil {
+			req.orderID = &id
+		}
+
+		if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
+			req.details["forwarded_for"] = fwd
+		}
+
+		auditID, err := a.startAudit(r, action, req)
+		if err != nil {
+			logger.Log.Errorw("admin api: failed to record audit entry, request refused",
+				"actor", actor,
+				"action", action,
+				"err", err,
+			)
+			writeError(w, http.StatusServiceUnavailable, "audit log unavailable")
+			return
+		}
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		fn(rec, r, req)
+
+		a.finishAudit(r, auditID, action, req, rec.status)
+	}
+}
+
+func (a *AdminAPI) authenticate(r *http.Request) (string, bool) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return "", false
+	}
+
+	hash := sha256.Sum256([]byte(token))
+	actor := ""
+	for _, t := range a.tokens {
+		if subtle.ConstantTimeCompare(hash[:], t.hash[:]) == 1 {
+			actor = t.name
+		}
+	}
+
+	return actor, actor != ""
+}
+
+func (a *AdminAPI) startAudit(r *http.Request, action string, req *adminRequest) (int64, error) {
+	ctx, cancel := context.WithTimeout(r.Context(), adminAuditTimeout)
+	defer cancel()
+
+	return a.auditService.Start(ctx, domain.AdminAuditEntry{
+		Actor:    req.actor,
+		Action:   action,
+		OrderID:  req.orderID,
+		Method:   r.Method,
+		Path:     r.URL.Path,
+		RemoteIP: remoteIP(r),
+		Details:  req.details,
+	})
+}
+
+// auditRejected records a request that failed authentication. It is
+// answered with 401 even if the entry can't be written.
+func (a *AdminAPI) auditRejected(r *http.Request, action string) {
+	ctx, cancel := context.WithTimeout(r.Context(), adminAuditTimeout)
+	defer cancel()
+
+	details := map[string]any{
+		"token_present": r.Header.Get("Authorization") != "",
+	}
+	if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
+		details["forwarded_for"] = fwd
+	}
+
+	status := http.StatusUnauthorized
+	entry := domain.AdminAuditEntry{
+		Actor:      adminAnonymousActor,
+		Action:     action,
+		Method:     r.Method,
+		Path:       r.URL.Path,
+		StatusCode: &status,
+		RemoteIP:   remoteIP(r),
+		Details:    details,
+	}
+	if id, err := strconv.Atoi(r.PathValue("id")); err == nil {
+		entry.OrderID = &id
+	}
+
+	if err := a.auditService.Record(ctx, entry); err != nil {
+		logger.Log.Errorw("admin api: failed to record rejected request",
+			"action", action,
+			"err", err,
+		)
+	}
+}
+
+func (a *AdminAPI) finishAudit(
+	r *http.Request,
+	id int64,
+	action string,
+	req *adminRequest,
+	status int,
+) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), adminAuditTimeout)
+	defer cancel()
+
+	if err := a.auditService.Finish(ctx, id, status, req.details); err != nil {
+		logger.Log.Errorw("admin api: failed to record audit outcome",
+			"audit_id", id,
+			"actor", req.actor,
+			"action", action,
+			"status", status,
+			"err", err,
+		)
+	}
+}
+
+type adminOrdersResponse struct {
+	Orders  []adminOrderItem `json:"orders"`
+	Total   int              `json:"total"`
+	Page    int              `json:"page"`
+	PerPage int              `json:"per_page"`
+}
+
+type adminOrderItem struct {
+	ID           int                `json:"id"`
+	Token        string             `json:"token"`
+	Status       domain.OrderStatus `json:"status"`
+	UserID       int                `json:"user_id"`
+	UserChatID   int64              `json:"user_chat_id"`
+	UserName     string             `json:"user_name"`
+	ExpertID     *int               `json:"expert_id"`
+	GameID       int                `json:"game_id"`
+	GameName     string             `json:"game_name"`
+	GameTypeID   int                `json:"game_type_id"`
+	GameTypeName string             `json:"game_type_name"`
+	CreatedAt    *time.Time         `json:"created_at"`
+	UpdatedAt    *time.Time         `json:"updated_at"`
+	PausedAt     *time.Time         `json:"paused_at"`
+}
+
+func (a *AdminAPI) handleList(w http.ResponseWriter, r *http.Request, req *adminRequest) {
+	query := r.URL.Query()
+
+	page, ok := queryInt(query.Get("page"), 1)
+	if !ok || page < 1 {
+		writeError(w, http.StatusBadRequest, "invalid page")
+		return
+	}
+
+	perPage, ok := queryInt(query.Get("per_page"), adminDefaultPerPage)
+	if !ok || perPage < 1 || perPage > adminMaxPerPage {
+		writeError(w, http.StatusBadRequest, "invalid per_page")
+		return
+	}
+
+	filter := domain.OrderFilter{
+		Status: domain.OrderStatus(query.Get("status")),
+		Limit:  perPage,
+		Offset: (page - 1) * perPage,
+	}
+
+	if filter.Status != "" && !validOrderStatus(filter.Status) {
+		writeError(w, http.StatusBadRequest, "invalid status")
+		return
+	}
+
+	if filter.GameID, ok = queryInt(query.Get("game_id"), 0); !ok || filter.GameID < 0 {
+		writeError(w, http.StatusBadRequest, "invalid game_id")
+		return
+	}
+
+	if filter.ExpertID, ok = queryInt(query.Get("expert_id"), 0); !ok || filter.ExpertID < 0 {
+		writeError(w, http.StatusBadRequest, "invalid expert_id")
+		return
+	}
+
+	if filter.From, ok = queryTime(query.Get("from"), false); !ok {
+		writeError(w, http.StatusBadRequest, "invalid from")
+		return
+	}
+
+	if filter.To, ok = queryTime(query.Get("to"), true); !ok {
+		writeError(w, http.StatusBadRequest, "invalid to")
+		return
+	}
+
+	orders, total, err := a.orderService.List(r.Context(), filter)
+	if err != nil {
+		logger.Log.Errorw("admin api: failed to list orders",
+			"actor", req.actor,
+			"err", err,
+		)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	resp := adminOrdersResponse{
+		Orders:  make([]adminOrderItem, 0, len(orders)),
+		Total:   total,
+		Page:    page,
+		PerPage: perPage,
+	}
+
+	for _, o := range orders {
+		resp.Orders = append(resp.Orders, adminOrderItem{
+			ID:           o.ID,
+			Token:        o.Token,
+			Status:       o.Status,
+			UserID:       o.UserID,
+			UserChatID:   o.UserChatID,
+			UserName:     o.UserNameAtPurchase,
+			ExpertID:     o.ExpertID,
+			GameID:       o.GameID,
+			GameName:     o.GameNameAtPurchase,
+			GameTypeID:   o.GameTypeID,
+			GameTypeName: o.GameTypeNameAtPurchase,
+			CreatedAt:    o.CreatedAt,
+			UpdatedAt:    o.UpdatedAt,
+			PausedAt:     o.PausedAt,
+		})
+	}
+
+	req.details["results"] = len(orders)
+	writeJSON(w, http.StatusOK, resp)
+}
+
+type adminTranscriptResponse struct {
+	Order    adminTranscriptOrder `json:"order"`
+	Messages []adminChatMessage   `json:"messages"`
+}
+
+type adminTranscriptOrder struct {
+	ID           int                `json:"id"`
+	Token        string             `json:"token"`
+	Status       domain.OrderStatus `json:"status"`
+	UserID       int                `json:"user_id"`
+	UserChatID   int64              `json:"user_chat_id"`
+	UserName     string             `json:"user_name"`
+	ExpertID     *int               `json:"expert_id"`
+	GameName     string             `json:"game_name"`
+	GameTypeName string             `json:"game_type_name"`
+	CreatedAt    *time.Time         `json:"created_at"`
+	UpdatedAt    *time.Time         `json:"updated_at"`
+}
+
+type adminChatMessage struct {
+	SenderRole  domain.SenderRole  `json:"sender_role"`
+	MessageType domain.MessageType `json:"message_type"`
+	Text        *string            `json:"text"`
+	Media       map[string]any     `json:"media,omitempty"`
+	IsRedacted  bool               `json:"is_redacted"`
+	CreatedAt   time.Time          `json:"created_at"`
+}
+
+func (a *AdminAPI) handleTranscript(w http.ResponseWriter, r *http.Request, req *adminRequest) {
+	if req.orderID == nil {
+		writeError(w, http.StatusBadRequest, "invalid order id")
+		return
+	}
+
+	of, err := a.orderService.FindByID(r.Context(), *req.orderID)
+	if err != nil {
+		a.writeOrderError(w, req, "failed to load transcript", err)
+		return
+	}
+
+	resp := adminTranscriptResponse{
+		Order: adminTranscriptOrder{
+			ID:           of.Order.ID,
+			Token:        of.Order.Token,
+			Status:       of.Order.Status,
+			UserID:       of.User.ID,
+			UserChatID:   of.User.ChatID,
+			UserName:     of.Order.UserNameAtPurchase,
+			GameName:     of.Order.GameNameAtPurchase,
+			GameTypeName: of.Order.GameTypeNameAtPurchase,
+			CreatedAt:    of.Order.CreatedAt,
+			UpdatedAt:    of.Order.UpdatedAt,
+		},
+		Messages: make([]adminChatMessage, 0, len(of.Messages)),
+	}
+	if of.Expert != nil && of.Expert.ID != 0 {
+		expertID := of.Expert.ID
+		resp.Order.ExpertID = &expertID
+	}
+
+	for _, m := range of.Messages {
+		resp.Messages = append(resp.Messages, adminChatMessage{
+			SenderRole:  m.SenderRole,
+			MessageType: m.MessageType,
+			Text:        m.Text,
+			Media:       m.Media,
+			IsRedacted:  m.IsRedacted,
+			CreatedAt:   m.CreatedAt,
+		})
+	}
+
+	req.details["messages"] = len(of.Messages)
+	writeJSON(w, http.StatusOK, resp)
+}
+
+type adminTransitionRequest struct {
+	Status domain.OrderStatus `json:"status"`
+}
+
+func (a *AdminAPI) handleTransition(w http.ResponseWriter, r *http.Request, req *adminRequest) {
+	if req.orderID == nil {
+		writeError(w, http.StatusBadRequest, "invalid order id")
+		return
+	}
+
+	var body adminTransitionRequest
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<10))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&body); err != nil || !validOrderStatus(body.Status) {
+		writeError(w, http.StatusBadRequest, "invalid status")
+		return
+	}
+	req.details["to"] = body.Status
+
+	if err := a.transitioner.TransitionOrder(r.Context(), *req.orderID, body.Status); err != nil {
+		a.writeOrderError(w, req, "failed to transition order", err)
+		return
+	}
+
+	logger.Log.Infow("admin api: order status changed",
+		"actor", req.actor,
+		"order_id", *req.orderID,
+		"to", body.Status,
+	)
+
+	writeJSON(w, http.StatusOK, adminTransitionRequest{Status: body.Status})
+}
+
+func (a *AdminAPI) writeOrderError(
+	w http.ResponseWriter,
+	req *adminRequest,
+	msg string,
+	err error,
+) {
+	req.details["error"] = err.Error()
+
+	switch {
+	case errors.Is(err, apperr.ErrNotFound):
+		writeError(w, http.StatusNotFound, "order not found")
+	case errors.Is(err, apperr.ErrConflict):
+		writeError(w, http.StatusConflict, "order status doesn't allow this change")
+	default:
+		logger.Log.Errorw("admin api: "+msg,
+			"actor", req.actor,
+			"order_id", req.orderID,
+			"err", err,
+		)
+		writeError(w, http.StatusInternalServerError, "internal error")
+	}
+}
+
+func validOrderStatus(s domain.OrderStatus) bool {
+	switch s {
+	case domain.OrderNew,
+		domain.OrderAccepted,
+		domain.OrderExpertConfirmed,
+		domain.OrderCompleted,
+		domain.OrderCanceled,
+		domain.OrderDeclined:
+		return true
+	}
+	return false
+}
+
+// queryTime accepts RFC 3339 timestamps or plain UTC dates. A date used as
+// an upper bound covers the whole day.
+func queryTime(v string, endOfDay bool) (*time.Time, bool) {
+	if v == "" {
+		return nil, true
+	}
+
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return &t, true
+	}
+
+	t, err := time.Parse(adminDateLayout, v)
+	if err != nil {
+		return nil, false
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, true
+}
@@ -0,0 +1,151 @@
+package httpapi
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/m4xvel/monetych_bot/internal/logger"
+)
+
+const healthCheckTimeout = 3 * time.Second
+
+// HealthCheck is one dependency probe. Details end up in the JSON response
+// next to the check status. Liveness checks also run for /healthz; they
+// should only fail when restarting the process would help.
+type HealthCheck struct {
+	Name     string
+	Liveness bool
+	Check    func(ctx context.Context) (map[string]any, error)
+}
+
+type HealthAPI struct {
+	startedAt time.Time
+	checks    []HealthCheck
+}
+
+func NewHealthAPI(checks ...HealthCheck) *HealthAPI {
+	return &HealthAPI{
+		startedAt: time.Now(),
+		checks:    checks,
+	}
+}
+
+func (a *HealthAPI) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /healthz", a.handleHealth)
+	mux.HandleFunc("GET /readyz", a.handleReady)
+}
+
+type healthResponse struct {
+	Status        string                 `json:"status"`
+	UptimeSeconds int64                  `json:"uptime_seconds"`
+	Checks        map[string]checkResult `json:"checks"`
+}
+
+type checkResult struct {
+	Status     string         `json:"status"`
+	Error      string         `json:"error,omitempty"`
+	DurationMS int64          `json:"duration_ms"`
+	Details    map[string]any `json:"details,omitempty"`
+}
+
+func (a *HealthAPI) handleHealth(w http.ResponseWriter, r *http.Request) {
+	a.respond(w, r, true)
+}
+
+func (a *HealthAPI) handleReady(w http.ResponseWriter, r *http.Request) {
+	a.respond(w, r, false)
+}
+
+func (a *HealthAPI) respond(
+	w http.ResponseWriter,
+	r *http.Request,
+	livenessOnly bool,
+) {
+	var checks []HealthCheck
+	for _, c := range a.checks {
+		if !livenessOnly || c.Liveness {
+			checks = append(checks, c)
+		}
+	}
+
+	results := a.run(r.Context(), checks)
+
+	resp := healthResponse{
+		Status:        "ok",
+		UptimeSeconds: int64(time.Since(a.startedAt).Seconds()),
+		Checks:        results,
+	}
+
+	status := http.StatusOK
+	for name, res := range results {
+		if res.Status == "ok" {
+			continue
+		}
+		resp.Status = "fail"
+		status = http.StatusServiceUnavailable
+		logger.Log.Warnw("health check failed",
+			"path", r.URL.Path,
+			"check", name,
+			"err", res.Error,
+		)
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, status, resp)
+}
+
+// run executes checks in parallel. A check still running at the deadline
+// is reported as failed; it is left to finish in the background.
+func (a *HealthAPI) run(
+	ctx context.Context,
+	checks []HealthCheck,
+) map[string]checkResult {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	type namedResult struct {
+		name   string
+		result checkResult
+	}
+
+	done := make(chan namedResult, len(checks))
+	for _, c := range checks {
+		go func() {
+			started := time.Now()
+			details, err := c.Check(ctx)
+
+			res := checkResult{
+				Status:     "ok",
+				DurationMS: time.Since(started).Milliseconds(),
+				Details:    details,
+			}
+			if err != nil {
+				res.Status = "fail"
+				res.Error = err.Error()
+			}
+			done <- namedResult{name: c.Name, result: res}
+		}()
+	}
+
+	results := make(map[string]checkResult, len(checks))
+	for range checks {
+		select {
+		case nr := <-done:
+			results[nr.name] = nr.result
+		case <-ctx.Done():
+			for _, c := range checks {
+				if _, ok := results[c.Name]; !ok {
+					results[c.Name] = checkResult{
+						Status:     "fail",
+						Error:      "timed out",
+						DurationMS: healthCheckTimeout.Milliseconds(),
+					}
+				}
+			}
+			return results
+		}
+	}
+
+	return results
+}
@@ -0,0 +1,71 @@
+package httpapi
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+
+	"github.com/m4xvel/monetych_bot/internal/logger"
+)
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		logger.Log.Warnw("failed to write http response",
+			"err", err,
+		)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, errorResponse{Error: message})
+}
+
+// writeCachedJSON sends body with an ETag derived from its content and
+// answers 304 when the client already has it.
+func writeCachedJSON(
+	w http.ResponseWriter,
+	r *http.Request,
+	body any,
+	cacheControl string,
+) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(body); err != nil {
+		logger.Log.Errorw("failed to encode http response",
+			"path", r.URL.Path,
+			"err", err,
+		)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	sum := sha256.Sum256(buf.Bytes())
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", cacheControl)
+
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodHead {
+		return
+	}
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		logger.Log.Warnw("failed to write http response",
+			"path", r.URL.Path,
+			"err", err,
+		)
+	}
+}
@@ -0,0 +1,178 @@
+package httpapi
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/m4xvel/monetych_bot/internal/features"
+	"github.com/m4xvel/monetych_bot/internal/logger"
+	"github.com/m4xvel/monetych_bot/internal/usecase"
+)
+
+const (
+	reviewsDefaultPerPage = 20
+	reviewsMaxPerPage     = 50
+	reviewsCacheControl   = "public, max-age=60"
+	avatarsCacheControl   = "public, max-age=604800, immutable"
+)
+
+// ReviewsAPI is the public read-only feed of published reviews for the
+// website, together with the avatars the feed links to.
+type ReviewsAPI struct {
+	reviewService *usecase.ReviewService
+}
+
+func NewReviewsAPI(rs *usecase.ReviewService) *ReviewsAPI {
+	return &ReviewsAPI{reviewService: rs}
+}
+
+func (a *ReviewsAPI) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/reviews", a.handleList)
+	mux.Handle("GET "+features.AvatarDir+"/", avatarsHandler())
+}
+
+type reviewsResponse struct {
+	Reviews []reviewItem `json:"reviews"`
+	Page    int          `json:"page"`
+	PerPage int          `json:"per_page"`
+	Pages   int          `json:"pages"`
+	Stats   reviewStats  `json:"stats"`
+}
+
+type reviewItem struct {
+	ID          int        `json:"id"`
+	Rating      int        `json:"rating"`
+	Text        *string    `json:"text"`
+	Game        string     `json:"game"`
+	GameType    string     `json:"game_type"`
+	Author      *string    `json:"author"`
+	AvatarURL   string     `json:"avatar_url"`
+	PublishedAt *time.Time `json:"published_at"`
+}
+
+type reviewStats struct {
+	Count    int            `json:"count"`
+	Average  float64        `json:"average"`
+	ByRating map[string]int `json:"by_rating"`
+}
+
+func (a *ReviewsAPI) handleList(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	page, ok := queryInt(query.Get("page"), 1)
+	if !ok || page < 1 {
+		writeError(w, http.StatusBadRequest, "invalid page")
+		return
+	}
+
+	perPage, ok := queryInt(query.Get("per_page"), reviewsDefaultPerPage)
+	if !ok || perPage < 1 || perPage > reviewsMaxPerPage {
+		writeError(w, http.StatusBadRequest, "invalid per_page")
+		return
+	}
+
+	gameID, ok := queryInt(query.Get("game_id"), 0)
+	if !ok || gameID < 0 {
+		writeError(w, http.StatusBadRequest, "invalid game_id")
+		return
+	}
+
+	ctx := r.Context()
+
+	stats, err := a.reviewService.GetPublishedStats(ctx, gameID)
+	if err != nil {
+		logger.Log.Errorw("reviews api: failed to get stats",
+			"game_id", gameID,
+			"err", err,
+		)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	reviews, err := a.reviewService.ListPublished(
+		ctx,
+		gameID,
+		perPage,
+		(page-1)*perPage,
+	)
+	if err != nil {
+		logger.Log.Errorw("reviews api: failed to list reviews",
+			"game_id", gameID,
+			"page", page,
+			"err", err,
+		)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	resp := reviewsResponse{
+		Reviews: make([]reviewItem, 0, len(reviews)),
+		Page:    page,
+		PerPage: perPage,
+		Pages:   (stats.Count + perPage - 1) / perPage,
+		Stats: reviewStats{
+			Count:    stats.Count,
+			Average:  stats.Average,
+			ByRating: make(map[string]int, len(stats.ByRating)),
+		},
+	}
+
+	for i, n := range stats.ByRating {
+		resp.Stats.ByRating[strconv.Itoa(i+1)] = n
+	}
+
+	for _, rv := range reviews {
+		item := reviewItem{
+			ID:          rv.ID,
+			Rating:      rv.Rating,
+			Text:        rv.Text,
+			Game:        rv.GameName,
+			GameType:    rv.GameTypeName,
+			AvatarURL:   features.DefaultAvatarURL,
+			PublishedAt: rv.PublishedAt,
+		}
+		if rv.AuthorName != "" {
+			author := rv.AuthorName
+			item.Author = &author
+		}
+		if rv.AuthorPhotoURL != "" {
+			item.AvatarURL = rv.AuthorPhotoURL
+		}
+		resp.Reviews = append(resp.Reviews, item)
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	writeCachedJSON(w, r, resp, reviewsCacheControl)
+}
+
+// avatarsHandler serves the avatar files saved by features.GetUserAvatar.
+// Their names are random, so they are cached for long and never listed.
+func avatarsHandler() http.Handler {
+	files := http.StripPrefix(
+		features.AvatarDir,
+		http.FileServer(http.Dir(features.AvatarDir)),
+	)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/") {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Cache-Control", avatarsCacheControl)
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		files.ServeHTTP(w, r)
+	})
+}
+
+func queryInt(value string, fallback int) (int, bool) {
+	if value == "" {
+		return fallback, true
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
@@ -0,0 +1,81 @@
+package httpapi
+
+import (
+	"crypto/subtle"
+	"net"
+	"net/http"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/m4xvel/monetych_bot/internal/logger"
+)
+
+const webhookSecretHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+// WebhookAPI receives updates from Telegram. Only requests carrying the
+// secret token passed to setWebhook are accepted, so knowing the path is
+// not enough to inject updates.
+type WebhookAPI struct {
+	bot     *tgbotapi.BotAPI
+	path    string
+	secret  []byte
+	updates chan tgbotapi.Update
+}
+
+func NewWebhookAPI(bot *tgbotapi.BotAPI, path, secret string) *WebhookAPI {
+	return &WebhookAPI{
+		bot:     bot,
+		path:    path,
+		secret:  []byte(secret),
+		updates: make(chan tgbotapi.Update, bot.Buffer),
+	}
+}
+
+func (a *WebhookAPI) Register(mux *http.ServeMux) {
+	mux.HandleFunc("POST "+a.path, a.handleUpdate)
+}
+
+func (a *WebhookAPI) Updates() tgbotapi.UpdatesChannel {
+	return a.updates
+}
+
+func (a *WebhookAPI) handleUpdate(w http.ResponseWriter, r *http.Request) {
+	token := []byte(r.Header.Get(webhookSecretHeader))
+	if subtle.ConstantTimeCompare(token, a.secret) != 1 {
+		logger.Log.Warnw("webhook request with invalid secret token rejected",
+			"remote_ip", remoteIP(r),
+			"forwarded_for", r.Header.Get("X-Forwarded-For"),
+			"token_present", len(token) > 0,
+		)
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	update, err := a.bot.HandleUpdate(r)
+	if err != nil {
+		logger.Log.Warnw("failed to decode webhook update",
+			"remote_ip", remoteIP(r),
+			"err", err,
+		)
+		writeError(w, http.StatusBadRequest, "invalid update")
+		return
+	}
+
+	select {
+	case a.updates <- *update:
+		w.WriteHeader(http.StatusOK)
+	case <-r.Context().Done():
+		// Telegram redelivers updates that weren't acknowledged.
+		logger.Log.Warnw("webhook request canceled before the update was queued",
+			"update_id", update.UpdateID,
+		)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+}
+
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"strings"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/m4xvel/monetych_bot/internal/apperr"
@@ -14,6 +13,7 @@ import (
 func (h *Handler) handleAcceptSelect(
 	ctx context.Context,
 	cb *tgbotapi.CallbackQuery,
+	payload AcceptOrderSelectPayload,
 ) {
 	chatID := cb.Message.Chat.ID
 	h.answerCallback(cb, "")
@@ -23,51 +23,19 @@ func (h *Handler) handleAcceptSelect(
 		"expert_chat_id", chatID,
 	)
 
-	parts := strings.Split(cb.Data, ":")
-	if len(parts) != 2 {
-		logger.Log.Warnw("invalid accept callback data",
-			"chat_id", chatID,
-			"data", cb.Data,
-		)
-		return
-	}
-
-	tokenCallback := parts[1]
-
-	var payload AcceptOrderSelectPayload
-
-	if err := h.callbackTokenService.Consume(
-		ctx,
-		tokenCallback,
-		"accept",
-		&payload,
-	); err != nil {
-		if isInvalidToken(err) {
-			logger.Log.Warnw("invalid accept callback token",
-				"chat_id", chatID,
-				"data", cb.Data,
-				"err", err,
-			)
-			return
-		}
-		logger.Log.Errorw("failed to consume accept callback token",
-			"chat_id", chatID,
-			"err", err,
-		)
-		return
-	}
-
 	chatUserID := payload.ChatID
 	messageUserID := payload.UserMessageID
 	orderID := payload.OrderID
 	expertID := payload.ExpertID
 
-	if err := h.callbackTokenService.DeleteByActionAndOrderID(
+	// The other experts' buttons and the client's cancel button go first,
+	// so nobody can act on the request while it is being accepted.
+	if err := h.invalidateCallbacks(
 		ctx,
-		"cancel",
+		surfaceOrderRequest,
 		orderID,
 	); err != nil {
-		logger.Log.Errorw("failed to delete cancel callbacks",
+		logger.Log.Errorw("failed to invalidate order request callbacks",
 			"order_id", orderID,
 			"err", err,
 		)
@@ -75,16 +43,6 @@ func (h *Handler) handleAcceptSelect(
 
 	if err := h.orderService.SetAcceptedStatus(ctx, orderID); err != nil {
 		if isOrderAlreadyProcessed(err) {
-			if err := h.callbackTokenService.DeleteByActionAndOrderID(
-				ctx,
-				"accept",
-				orderID,
-			); err != nil {
-				logger.Log.Errorw("failed to delete accept callbacks",
-					"order_id", orderID,
-					"err", err,
-				)
-			}
 			logger.Log.Infow("order already processed on accept",
 				"order_id", orderID,
 				"err", err,
@@ -95,20 +53,10 @@ func (h *Handler) handleAcceptSelect(
 			"order_id", orderID,
 			"err", err,
 		)
+		releaseCallback(ctx)
 		return
 	}
 
-	if err := h.callbackTokenService.DeleteByActionAndOrderID(
-		ctx,
-		"accept",
-		orderID,
-	); err != nil {
-		logger.Log.Errorw("failed to delete accept callbacks",
-			"order_id", orderID,
-			"err", err,
-		)
-	}
-
 	logger.Log.Infow("order accepted",
 		"order_id", orderID,
 		"expert_id", expertID,
@@ -133,6 +81,7 @@ func (h *Handler) handleAcceptSelect(
 	}
 
 	threadID, err := h.createForumTopic(
+		ctx,
 		h.textDynamic.TitleOrderTopic(
 			orderID,
 			order.GameNameAtPurchase,
@@ -165,43 +114,40 @@ func (h *Handler) handleAcceptSelect(
 
 	h.deleteOrderMessage(ctx, orderID)
 
-	if _, err := h.bot.Send(tgbotapi.NewMessage(
-		chatID,
-		h.textDynamic.AssessorAcceptedOrder(orderID, order.GameNameAtPurchase, order.GameTypeNameAtPurchase),
-	)); err != nil {
-		wrapped := wrapTelegramErr("telegram.send_expert_accept_notice", err)
-		logger.Log.Errorw("failed to send expert accept notice",
-			"order_id", orderID,
-			"expert_id", expertID,
-			"err", wrapped,
-		)
-	}
+	h.post(
+		tgbotapi.NewMessage(
+			chatID,
+			h.textDynamic.AssessorAcceptedOrder(orderID, order.GameNameAtPurchase, order.GameTypeNameAtPurchase),
+		),
+		"telegram.send_expert_accept_notice",
+		nil,
+		"order_id", orderID,
+		"expert_id", expertID,
+	)
 
 	h.renderControlPanel(ctx, expert.TopicID, threadID, order)
 
-	if _, err := h.bot.Request(tgbotapi.NewDeleteMessage(chatUserID, messageUserID)); err != nil {
-		wrapped := wrapTelegramErr("telegram.delete_user_message", err)
-		logger.Log.Errorw("failed to delete user message",
-			"user_chat_id", chatUserID,
-			"message_id", messageUserID,
-			"err", wrapped,
-		)
-	}
+	h.post(
+		tgbotapi.NewDeleteMessage(chatUserID, messageUserID),
+		"telegram.delete_user_message",
+		nil,
+		"user_chat_id", chatUserID,
+		"message_id", messageUserID,
+	)
 
 	message := tgbotapi.NewMessage(
 		chatUserID,
-		h.textDynamic.AssessorAcceptedYourOrder(order.Token),
+		h.textDynamic.AssessorAcceptedYourOrder(
+			order.Token,
+			h.expertRatingText(expertID),
+		),
 	)
 	message.ParseMode = "Markdown"
 
-	if _, err := h.bot.Send(message); err != nil {
-		wrapped := wrapTelegramErr("telegram.send_accept_to_user", err)
-		logger.Log.Errorw("failed to notify user about accepted order",
-			"order_id", orderID,
-			"user_chat_id", chatUserID,
-			"err", wrapped,
-		)
-	}
+	h.post(message, "telegram.send_accept_to_user", nil,
+		"order_id", orderID,
+		"user_chat_id", chatUserID,
+	)
 
 	if err := h.stateService.SetStateCommunication(ctx, chatUserID, &orderID); err != nil {
 		logger.Log.Errorw("failed to set communication state",
@@ -213,6 +159,7 @@ func (h *Handler) handleAcceptSelect(
 }
 
 func (h *Handler) createForumTopic(
+	ctx context.Context,
 	topicName string,
 	topicID int64,
 ) (int64, error) {
@@ -222,7 +169,7 @@ func (h *Handler) createForumTopic(
 		"name":    topicName,
 	}
 
-	resp, err := h.bot.MakeRequest("createForumTopic", params)
+	resp, err := h.makeRequest(ctx, "createForumTopic", params)
 	if err != nil {
 		wrapped := wrapTelegramErr("telegram.create_forum_topic", err)
 		logger.Log.Errorw("telegram createForumTopic request failed",
@@ -254,3 +201,11 @@ func (h *Handler) createForumTopic(
 
 	return topic.MessageThreadID, nil
 }
+
+func (h *Handler) expertRatingText(expertID int) string {
+	rating, ok := h.expertRatingService.Get(expertID)
+	if !ok || rating.Count == 0 {
+		return ""
+	}
+	return h.textDynamic.ExpertRating(rating.Average, rating.Count, rating.Trend())
+}
@@ -3,7 +3,6 @@ package telegram
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/m4xvel/monetych_bot/internal/logger"
@@ -12,6 +11,7 @@ import (
 func (h *Handler) handleAcceptClientSelect(
 	ctx context.Context,
 	cb *tgbotapi.CallbackQuery,
+	payload CancelOrderSelectPayload,
 ) {
 	chatID := cb.Message.Chat.ID
 	messageID := cb.Message.MessageID
@@ -22,40 +22,6 @@ func (h *Handler) handleAcceptClientSelect(
 		"callback_data", cb.Data,
 	)
 
-	parts := strings.Split(cb.Data, ":")
-	if len(parts) != 2 {
-		logger.Log.Warnw("invalid accept client callback data",
-			"chat_id", chatID,
-			"data", cb.Data,
-		)
-		return
-	}
-
-	tokenCallback := parts[1]
-
-	var payload CancelOrderSelectPayload
-
-	if err := h.callbackTokenService.Consume(
-		ctx,
-		tokenCallback,
-		"accept_client",
-		&payload,
-	); err != nil {
-		if isInvalidToken(err) {
-			logger.Log.Warnw("invalid accept client callback token",
-				"chat_id", chatID,
-				"data", cb.Data,
-				"err", err,
-			)
-			return
-		}
-		logger.Log.Errorw("failed to consume accept client callback token",
-			"chat_id", chatID,
-			"err", err,
-		)
-		return
-	}
-
 	orderID := payload.OrderID
 
 	if err := h.orderService.SetCompletedStatus(ctx, orderID, chatID); err != nil {
@@ -72,6 +38,7 @@ func (h *Handler) handleAcceptClientSelect(
 			"order_id", orderID,
 			"err", err,
 		)
+		releaseCallback(ctx)
 		return
 	}
 
@@ -94,39 +61,49 @@ func (h *Handler) handleAcceptClientSelect(
 		msg := tgbotapi.NewMessage(*order.TopicID, h.text.OrderConfirmed)
 		msg.MessageThreadID = *order.ThreadID
 
-		if _, err := h.bot.Send(msg); err != nil {
-			wrapped := wrapTelegramErr("telegram.notify_expert_completion", err)
-			logger.Log.Errorw("failed to notify expert about order completion",
-				"order_id", orderID,
-				"topic_id", *order.TopicID,
-				"err", wrapped,
-			)
-		}
+		h.post(msg, "telegram.notify_expert_completion", nil,
+			"order_id", orderID,
+			"topic_id", *order.TopicID,
+		)
 	}
 
-	if _, err := h.bot.Request(
+	h.post(
 		tgbotapi.NewEditMessageText(
 			chatID,
 			messageID,
 			h.text.YouConfirmedPayment,
 		),
-	); err != nil {
-		wrapped := wrapTelegramErr("telegram.edit_client_confirmation", err)
-		logger.Log.Errorw("failed to edit client confirmation message",
+		"telegram.edit_client_confirmation",
+		nil,
+		"chat_id", chatID,
+		"order_id", orderID,
+	)
+
+	h.sendRatePrompt(ctx, chatID, orderID, h.text.ChatClosedText)
+
+	if err := h.stateService.SetStateIdle(ctx, chatID); err != nil {
+		logger.Log.Errorw("failed to set idle state after order completion",
 			"chat_id", chatID,
 			"order_id", orderID,
-			"err", wrapped,
+			"err", err,
 		)
 	}
+}
 
+func (h *Handler) sendRatePrompt(
+	ctx context.Context,
+	chatID int64,
+	orderID int,
+	text string,
+) {
 	buttons := make([]tgbotapi.InlineKeyboardButton, 0, 5)
 	rateTokens := make([]string, 0, 5)
 
 	for i := 1; i <= 5; i++ {
-		token, err := h.callbackTokenService.Create(
+		token, err := rateAction.Create(
 			ctx,
-			"rate",
-			&RateSelectPayload{
+			h,
+			RateSelectPayload{
 				ChatID:  chatID,
 				Rate:    i,
 				OrderID: orderID,
@@ -140,25 +117,19 @@ func (h *Handler) handleAcceptClientSelect(
 		buttons = append(buttons,
 			tgbotapi.NewInlineKeyboardButtonData(
 				fmt.Sprintf("⭐ %d", i),
-				"rate:"+token,
+				rateAction.Data(token),
 			),
 		)
 	}
 
-	rateMsg := tgbotapi.NewMessage(chatID, h.text.ChatClosedText)
+	rateMsg := tgbotapi.NewMessage(chatID, text)
 	rateMsg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(buttons...),
 	)
 
-	if _, err := h.bot.Send(rateMsg); err != nil {
-		wrapped := wrapTelegramErr("telegram.send_rate_prompt", err)
-		logger.Log.Errorw("failed to send rate prompt to client",
-			"chat_id", chatID,
-			"order_id", orderID,
-			"err", wrapped,
-		)
+	dropTokens := func(error) {
 		for _, token := range rateTokens {
-			if err := h.callbackTokenService.Delete(ctx, token, "rate"); err != nil {
+			if err := rateAction.Delete(ctx, h, token); err != nil {
 				logger.Log.Errorw("failed to cleanup rate callback token",
 					"chat_id", chatID,
 					"order_id", orderID,
@@ -168,11 +139,8 @@ func (h *Handler) handleAcceptClientSelect(
 		}
 	}
 
-	if err := h.stateService.SetStateIdle(ctx, chatID); err != nil {
-		logger.Log.Errorw("failed to set idle state after order completion",
-			"chat_id", chatID,
-			"order_id", orderID,
-			"err", err,
-		)
-	}
+	h.post(rateMsg, "telegram.send_rate_prompt", dropTokens,
+		"chat_id", chatID,
+		"order_id", orderID,
+	)
 }
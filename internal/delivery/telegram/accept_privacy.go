@@ -2,7 +2,6 @@ package telegram
 
 import (
 	"context"
-	"strings"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/m4xvel/monetych_bot/internal/logger"
@@ -11,44 +10,11 @@ import (
 func (h *Handler) handleAcceptPrivacySelect(
 	ctx context.Context,
 	cb *tgbotapi.CallbackQuery,
+	payload AcceptPrivacySelectPayload,
 ) {
 	chatID := cb.Message.Chat.ID
 	h.answerCallback(cb, "")
 
-	parts := strings.Split(cb.Data, ":")
-	if len(parts) != 2 {
-		logger.Log.Warnw("invalid accept client callback data",
-			"chat_id", chatID,
-			"data", cb.Data,
-		)
-		return
-	}
-
-	tokenCallback := parts[1]
-
-	var payload AcceptPrivacySelectPayload
-
-	if err := h.callbackTokenService.Consume(
-		ctx,
-		tokenCallback,
-		"accept_privacy",
-		&payload,
-	); err != nil {
-		if isInvalidToken(err) {
-			logger.Log.Warnw("invalid accept privacy callback token",
-				"chat_id", chatID,
-				"data", cb.Data,
-				"err", err,
-			)
-			return
-		}
-		logger.Log.Errorw("failed to consume accept privacy callback token",
-			"chat_id", chatID,
-			"err", err,
-		)
-		return
-	}
-
 	userID := payload.ChatID
 
 	if err := h.userService.AddUser(
@@ -63,6 +29,7 @@ func (h *Handler) handleAcceptPrivacySelect(
 			"chat_id", chatID,
 			"err", err,
 		)
+		releaseCallback(ctx)
 		return
 	}
 
@@ -78,6 +45,7 @@ func (h *Handler) handleAcceptPrivacySelect(
 			"chat_id", chatID,
 			"err", err,
 		)
+		releaseCallback(ctx)
 		return
 	}
 
@@ -89,11 +57,9 @@ func (h *Handler) handleAcceptPrivacySelect(
 		},
 	}
 
-	_, err := h.bot.Send(edit)
-	if err != nil {
-		wrapped := wrapTelegramErr("telegram.remove_accept_privacy_keyboard", err)
-		logger.Log.Errorw("failed to remove keyboard", "err", wrapped)
-	}
+	h.post(edit, "telegram.remove_accept_privacy_keyboard", nil,
+		"chat_id", chatID,
+	)
 
 	h.handlerCatalogCommand(ctx, cb.Message)
 }
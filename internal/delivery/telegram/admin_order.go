@@ -0,0 +1,74 @@
+package telegram
+
+import (
+	"context"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/m4xvel/monetych_bot/internal/domain"
+	"github.com/m4xvel/monetych_bot/internal/logger"
+	"github.com/m4xvel/monetych_bot/internal/usecase"
+)
+
+// TransitionOrder applies a status change requested through the admin API
+// and cleans up in Telegram the way the matching bot flow would.
+func (h *Handler) TransitionOrder(
+	ctx context.Context,
+	orderID int,
+	to domain.OrderStatus,
+) error {
+	order, err := h.orderService.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return err
+	}
+
+	switch to {
+	case domain.OrderCanceled:
+		return h.cancelOrderBySupport(ctx, *order)
+	default:
+		return usecase.ErrTransitionNotAllowed
+	}
+}
+
+func (h *Handler) cancelOrderBySupport(ctx context.Context, order domain.Order) error {
+	if err := h.orderService.CancelBySupport(ctx, order); err != nil {
+		return err
+	}
+
+	for _, surface := range []callbackSurface{surfaceOrderRequest, surfaceControlPanel} {
+		if err := h.invalidateCallbacks(ctx, surface, order.ID); err != nil {
+			logger.Log.Errorw("failed to invalidate callbacks after support cancel",
+				"order_id", order.ID,
+				"surface", surface,
+				"err", err,
+			)
+		}
+	}
+
+	if order.Status == domain.OrderNew {
+		h.deleteOrderMessage(ctx, order.ID)
+	} else {
+		h.notifyExpertThread(
+			order.TopicID,
+			order.ThreadID,
+			h.text.ExpertOrderCanceledBySupportText,
+			"telegram.send_order_canceled_by_support",
+			order.ID,
+		)
+	}
+
+	msg := tgbotapi.NewMessage(order.UserChatID, h.text.OrderCanceledBySupportText)
+	h.post(msg, "telegram.notify_user_canceled_by_support", nil,
+		"order_id", order.ID,
+		"user_chat_id", order.UserChatID,
+	)
+
+	if err := h.stateService.SetStateIdle(ctx, order.UserChatID); err != nil {
+		logger.Log.Errorw("failed to set user idle after support cancel",
+			"order_id", order.ID,
+			"user_chat_id", order.UserChatID,
+			"err", err,
+		)
+	}
+
+	return nil
+}
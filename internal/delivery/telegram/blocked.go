@@ -0,0 +1,228 @@
+package telegram
+
+import (
+	"context"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/m4xvel/monetych_bot/internal/logger"
+)
+
+const unreachableHandlingTimeout = 10 * time.Second
+
+func (h *Handler) handleMyChatMember(
+	ctx context.Context,
+	upd *tgbotapi.ChatMemberUpdated,
+) {
+	if !upd.Chat.IsPrivate() {
+		return
+	}
+
+	chatID := upd.Chat.ID
+
+	switch upd.NewChatMember.Status {
+	case "kicked":
+		logger.Log.Infow("user blocked the bot",
+			"chat_id", chatID,
+		)
+		h.markUserUnreachable(ctx, chatID)
+
+	case "member":
+		logger.Log.Infow("user unblocked the bot",
+			"chat_id", chatID,
+		)
+		h.markUserReachable(ctx, chatID)
+	}
+}
+
+func (h *Handler) markUserUnreachable(
+	ctx context.Context,
+	chatID int64,
+) {
+	if err := h.userService.SetReachable(ctx, chatID, false); err != nil {
+		logger.Log.Errorw("failed to mark user unreachable",
+			"chat_id", chatID,
+			"err", err,
+		)
+	}
+
+	orders, err := h.orderService.PauseActiveByUser(ctx, chatID)
+	if err != nil {
+		logger.Log.Errorw("failed to pause orders for unreachable user",
+			"chat_id", chatID,
+			"err", err,
+		)
+		return
+	}
+
+	for _, order := range orders {
+		h.notifyExpertThread(
+			order.TopicID,
+			order.ThreadID,
+			h.textDynamic.UserBlockedBotWarning(int(h.orderPauseGrace.Hours())),
+			"telegram.send_user_blocked_warning",
+			order.ID,
+		)
+	}
+}
+
+func (h *Handler) markUserReachable(
+	ctx context.Context,
+	chatID int64,
+) {
+	if err := h.userService.SetReachable(ctx, chatID, true); err != nil {
+		logger.Log.Errorw("failed to mark user reachable",
+			"chat_id", chatID,
+			"err", err,
+		)
+	}
+
+	orders, err := h.orderService.ResumeActiveByUser(ctx, chatID)
+	if err != nil {
+		logger.Log.Errorw("failed to resume orders for reachable user",
+			"chat_id", chatID,
+			"err", err,
+		)
+		return
+	}
+
+	for _, order := range orders {
+		h.notifyExpertThread(
+			order.TopicID,
+			order.ThreadID,
+			h.text.UserUnblockedBotText,
+			"telegram.send_user_unblocked_notice",
+			order.ID,
+		)
+	}
+}
+
+func (h *Handler) handleUndeliverable(userChatID int64, err error) {
+	if !isBotBlocked(err) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(
+		context.Background(),
+		unreachableHandlingTimeout,
+	)
+	defer cancel()
+
+	logger.Log.Warnw("message undeliverable, user blocked the bot",
+		"chat_id", userChatID,
+		"err", err,
+	)
+
+	h.markUserUnreachable(ctx, userChatID)
+}
+
+func (h *Handler) CancelStalePausedOrders(ctx context.Context) {
+	before := time.Now().Add(-h.orderPauseGrace)
+
+	orders, err := h.orderService.CancelPausedBefore(ctx, before)
+	if err != nil {
+		logger.Log.Errorw("failed to cancel stale paused orders",
+			"before", before,
+			"err", err,
+		)
+		return
+	}
+
+	for _, order := range orders {
+		logger.Log.Infow("paused order auto canceled",
+			"order_id", order.ID,
+			"user_chat_id", order.UserChatID,
+			"paused_at", order.PausedAt,
+		)
+
+		h.notifyExpertThread(
+			order.TopicID,
+			order.ThreadID,
+			h.text.OrderAutoCanceledText,
+			"telegram.send_order_auto_canceled",
+			order.ID,
+		)
+
+		if err := h.stateService.SetStateIdle(ctx, order.UserChatID); err != nil {
+			logger.Log.Errorw("failed to set idle state after auto cancel",
+				"order_id", order.ID,
+				"user_chat_id", order.UserChatID,
+				"err", err,
+			)
+		}
+	}
+}
+
+// returningAfterBlock reports whether /start comes from a user who blocked
+// the bot during the order: the user is still marked unreachable or the
+// order is still paused.
+func (h *Handler) returningAfterBlock(
+	ctx context.Context,
+	uc *UpdateContext,
+	orderID *int,
+) bool {
+	if user, err := uc.User(ctx); err == nil && user != nil && !user.IsReachable {
+		return true
+	}
+
+	if orderID == nil {
+		return false
+	}
+
+	order, err := h.orderService.GetOrderByID(ctx, *orderID)
+	if err != nil || order == nil {
+		return false
+	}
+
+	return order.PausedAt != nil
+}
+
+func (h *Handler) sendResumePrompt(
+	ctx context.Context,
+	chatID int64,
+	orderID *int,
+) {
+	if orderID == nil {
+		return
+	}
+
+	order, err := h.orderService.GetOrderByID(ctx, *orderID)
+	if err != nil || order == nil {
+		logger.Log.Errorw("failed to get order for resume prompt",
+			"chat_id", chatID,
+			"order_id", *orderID,
+			"err", err,
+		)
+		return
+	}
+
+	message := tgbotapi.NewMessage(
+		chatID,
+		h.textDynamic.ResumeOrderPrompt(order.Token),
+	)
+	message.ParseMode = "Markdown"
+
+	h.post(message, "telegram.send_resume_prompt", nil,
+		"chat_id", chatID,
+		"order_id", order.ID,
+	)
+}
+
+func (h *Handler) notifyExpertThread(
+	topicID, threadID *int64,
+	text string,
+	op string,
+	orderID int,
+) {
+	if topicID == nil || threadID == nil {
+		return
+	}
+
+	msg := tgbotapi.NewMessage(*topicID, text)
+	msg.MessageThreadID = *threadID
+
+	h.post(msg, op, nil,
+		"order_id", orderID,
+		"topic_id", *topicID,
+	)
+}
@@ -0,0 +1,395 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/m4xvel/monetych_bot/internal/apperr"
+	"github.com/m4xvel/monetych_bot/internal/callbackdata"
+	"github.com/m4xvel/monetych_bot/internal/domain"
+	"github.com/m4xvel/monetych_bot/internal/logger"
+)
+
+// CallbackAction ties a callback action name to its payload type, so a
+// button and the handler consuming it cannot disagree on either.
+type CallbackAction[T any] struct {
+	name    string
+	ttl     time.Duration
+	owner   func(T) int64
+	invalid func(h *Handler) string
+	signed  *signedCodec[T]
+	surface callbackSurface
+	orderID func(T) int
+}
+
+// callbackSurface names a group of buttons of one order that are
+// invalidated together, e.g. the whole expert control panel.
+type callbackSurface string
+
+const (
+	surfaceOrderRequest     callbackSurface = "request"
+	surfaceControlPanel     callbackSurface = "panel"
+	surfaceVerify           callbackSurface = "verify"
+	surfaceRate             callbackSurface = "rate"
+	surfaceTemplates        callbackSurface = "templates"
+	surfaceReviewModeration callbackSurface = "review"
+)
+
+func (s callbackSurface) scope(orderID int) string {
+	return fmt.Sprintf("order:%d:%s", orderID, s)
+}
+
+type signedCodec[T any] struct {
+	encode func(T) []int64
+	decode func([]int64) (T, bool)
+}
+
+type TypedCallbackFunc[T any] func(
+	ctx context.Context,
+	cb *tgbotapi.CallbackQuery,
+	payload T,
+)
+
+func NewCallbackAction[T any](name string) CallbackAction[T] {
+	return CallbackAction[T]{name: name}
+}
+
+// ExpiresIn limits how long buttons of this action stay usable; zero
+// keeps them until consumed or deleted.
+func (a CallbackAction[T]) ExpiresIn(ttl time.Duration) CallbackAction[T] {
+	a.ttl = ttl
+	return a
+}
+
+// Signed makes buttons carry the payload in HMAC-signed callback data
+// instead of a database row. Such buttons can be pressed more than once,
+// so it is only for navigation; one-shot actions keep database tokens.
+func (a CallbackAction[T]) Signed(
+	encode func(T) []int64,
+	decode func([]int64) (T, bool),
+) CallbackAction[T] {
+	a.signed = &signedCodec[T]{encode: encode, decode: decode}
+	return a
+}
+
+// InSurface files tokens of this action under the order surface so they
+// can be dropped with invalidateCallbacks.
+func (a CallbackAction[T]) InSurface(
+	surface callbackSurface,
+	orderID func(T) int,
+) CallbackAction[T] {
+	a.surface = surface
+	a.orderID = orderID
+	return a
+}
+
+// OwnedBy drops presses from anyone but the chat returned for the payload.
+func (a CallbackAction[T]) OwnedBy(owner func(T) int64) CallbackAction[T] {
+	a.owner = owner
+	return a
+}
+
+// OnInvalid sets the toast shown when the token is unknown or already used.
+func (a CallbackAction[T]) OnInvalid(text func(h *Handler) string) CallbackAction[T] {
+	a.invalid = text
+	return a
+}
+
+func (a CallbackAction[T]) Name() string {
+	return a.name
+}
+
+func (a CallbackAction[T]) Data(token string) string {
+	return a.name + ":" + token
+}
+
+func (a CallbackAction[T]) Create(
+	ctx context.Context,
+	h *Handler,
+	payload T,
+) (string, error) {
+	if a.signed != nil {
+		return h.callbackSigner.Sign(
+			a.name,
+			a.signed.encode(payload),
+			time.Now().Add(a.ttl),
+		)
+	}
+
+	var scope string
+	if a.orderID != nil {
+		scope = a.surface.scope(a.orderID(payload))
+	}
+	return h.callbackTokenService.Create(ctx, a.name, scope, payload, a.ttl)
+}
+
+func (a CallbackAction[T]) Delete(
+	ctx context.Context,
+	h *Handler,
+	token string,
+) error {
+	if a.signed != nil {
+		return nil
+	}
+	return h.callbackTokenService.Delete(ctx, token, a.name)
+}
+
+func (h *Handler) invalidateCallbacks(
+	ctx context.Context,
+	surface callbackSurface,
+	orderID int,
+) error {
+	return h.callbackTokenService.DeleteByScope(ctx, surface.scope(orderID))
+}
+
+const (
+	menuCallbackTTL     = 24 * time.Hour
+	longCallbackTTL     = 7 * 24 * time.Hour
+	templateCallbackTTL = time.Hour
+)
+
+var (
+	acceptPrivacyAction = NewCallbackAction[AcceptPrivacySelectPayload]("accept_privacy").
+				ExpiresIn(longCallbackTTL)
+
+	gameAction = NewCallbackAction[GameSelectPayload]("game").
+			ExpiresIn(menuCallbackTTL).
+			OwnedBy(func(p GameSelectPayload) int64 { return p.ChatID }).
+			Signed(encodeGameSelect, decodeGameSelect)
+	typeAction = NewCallbackAction[TypeSelectPayload]("type").
+			ExpiresIn(menuCallbackTTL).
+			OwnedBy(func(p TypeSelectPayload) int64 { return p.ChatID }).
+			Signed(encodeTypeSelect, decodeTypeSelect)
+	orderAction = NewCallbackAction[OrderSelectPayload]("order").
+			ExpiresIn(menuCallbackTTL).
+			OwnedBy(func(p OrderSelectPayload) int64 { return p.ChatID })
+	cancelAction = NewCallbackAction[CancelOrderSelectPayload]("cancel").
+			InSurface(surfaceOrderRequest, func(p CancelOrderSelectPayload) int { return p.OrderID }).
+			OwnedBy(func(p CancelOrderSelectPayload) int64 { return p.ChatID })
+	acceptAction = NewCallbackAction[AcceptOrderSelectPayload]("accept").
+			InSurface(surfaceOrderRequest, func(p AcceptOrderSelectPayload) int { return p.OrderID })
+
+	confirmedAction         = newPanelAction("confirmed")
+	declinedAction          = newPanelAction("declined")
+	confirmedReaffirmAction = newPanelAction("confirmed_reaffirm")
+	declinedReaffirmAction  = newPanelAction("declined_reaffirm")
+	backAction              = newPanelAction("back")
+	verificationAction      = NewCallbackAction[VerificationSelectPayload]("verification").
+				InSurface(surfaceControlPanel, func(p VerificationSelectPayload) int { return p.OrderID })
+
+	acceptClientAction = NewCallbackAction[CancelOrderSelectPayload]("accept_client")
+
+	rateAction = NewCallbackAction[RateSelectPayload]("rate").
+			ExpiresIn(longCallbackTTL).
+			InSurface(surfaceRate, func(p RateSelectPayload) int { return p.OrderID })
+	verifyAction = NewCallbackAction[VerificationSelectPayload]("verify").
+			ExpiresIn(longCallbackTTL).
+			InSurface(surfaceVerify, func(p VerificationSelectPayload) int { return p.OrderID }).
+			OwnedBy(func(p VerificationSelectPayload) int64 { return p.UserChatID })
+	showMediaAction = NewCallbackAction[SearchPayload]("show_media").
+			ExpiresIn(menuCallbackTTL).
+			OwnedBy(func(p SearchPayload) int64 { return p.ChatID })
+
+	templateAction = NewCallbackAction[TemplateSelectPayload]("tpl").
+			ExpiresIn(templateCallbackTTL).
+			InSurface(surfaceTemplates, func(p TemplateSelectPayload) int { return p.OrderID }).
+			OwnedBy(func(p TemplateSelectPayload) int64 { return p.ExpertUserID }).
+			OnInvalid(func(h *Handler) string { return h.text.TemplateUnavailableToast })
+
+	reviewApproveAction        = newModerationAction("review_ok")
+	reviewRejectAction         = newModerationAction("review_rej")
+	reviewRejectReasonAction   = newModerationAction("review_rsn")
+	reviewModerationBackAction = newModerationAction("review_back")
+
+	reviewAnonymousAction = NewCallbackAction[ReviewAnonymousPayload]("review_anon").
+				ExpiresIn(longCallbackTTL).
+				OwnedBy(func(p ReviewAnonymousPayload) int64 { return p.ChatID })
+	reviewsPageAction = NewCallbackAction[ReviewsPagePayload]("reviews").
+				ExpiresIn(menuCallbackTTL).
+				OwnedBy(func(p ReviewsPagePayload) int64 { return p.ChatID }).
+				Signed(encodeReviewsPage, decodeReviewsPage)
+)
+
+func newPanelAction(name string) CallbackAction[ConfirmedAndDeclinedOrderSelectPayload] {
+	return NewCallbackAction[ConfirmedAndDeclinedOrderSelectPayload](name).
+		InSurface(surfaceControlPanel, func(p ConfirmedAndDeclinedOrderSelectPayload) int {
+			return p.OrderID
+		})
+}
+
+func newModerationAction(name string) CallbackAction[ReviewModerationPayload] {
+	return NewCallbackAction[ReviewModerationPayload](name).
+		InSurface(surfaceReviewModeration, func(p ReviewModerationPayload) int {
+			return p.OrderID
+		})
+}
+
+func encodeGameSelect(p GameSelectPayload) []int64 {
+	return []int64{p.ChatID, int64(p.GameID)}
+}
+
+func decodeGameSelect(f []int64) (GameSelectPayload, bool) {
+	if len(f) != 2 {
+		return GameSelectPayload{}, false
+	}
+	return GameSelectPayload{ChatID: f[0], GameID: int(f[1])}, true
+}
+
+func encodeTypeSelect(p TypeSelectPayload) []int64 {
+	return []int64{p.ChatID, int64(p.GameID), int64(p.TypeID)}
+}
+
+func decodeTypeSelect(f []int64) (TypeSelectPayload, bool) {
+	if len(f) != 3 {
+		return TypeSelectPayload{}, false
+	}
+	return TypeSelectPayload{
+		ChatID: f[0],
+		GameID: int(f[1]),
+		TypeID: int(f[2]),
+	}, true
+}
+
+// RegisterTyped routes "<action>:<token>" callbacks to handler with the
+// decoded payload. Malformed data, dead or expired tokens and foreign
+// presses are answered and dropped here; transient store errors release
+// the dedup lock so the press can be retried. Handlers call releaseCallback
+// on their own transient failures, which also puts a consumed token back.
+func RegisterTyped[T any](
+	h *Handler,
+	action CallbackAction[T],
+	handler TypedCallbackFunc[T],
+) {
+	h.router.RegisterCallback(action.name+":", func(
+		ctx context.Context,
+		cb *tgbotapi.CallbackQuery,
+	) {
+		chatID := cb.Message.Chat.ID
+
+		token, ok := strings.CutPrefix(cb.Data, action.name+":")
+		if !ok || token == "" || strings.Contains(token, ":") {
+			logger.Log.Warnw("invalid callback data",
+				"action", action.name,
+				"chat_id", chatID,
+				"data", cb.Data,
+			)
+			h.answerCallback(cb, "")
+			return
+		}
+
+		ignoreForeign := func() {
+			logger.Log.Warnw("callback from foreign user ignored",
+				"action", action.name,
+				"chat_id", chatID,
+				"user_id", cb.From.ID,
+			)
+			h.answerCallback(cb, "")
+		}
+
+		// Stored tokens are one-shot, so a foreign press must be turned away
+		// before the token is consumed or it burns the owner's button.
+		if action.owner != nil && action.signed == nil {
+			if owner, ok := action.peekOwner(ctx, h, token); ok && owner != cb.From.ID {
+				ignoreForeign()
+				return
+			}
+		}
+
+		payload, consumed, err := action.decode(ctx, h, token)
+		if err != nil {
+			if isExpiredToken(err) {
+				callbackTokenFailures.Inc(action.name, "expired")
+				logger.Log.Infow("expired callback token",
+					"action", action.name,
+					"chat_id", chatID,
+				)
+				h.answerCallbackAlert(cb, h.text.CallbackExpiredToast)
+				return
+			}
+			if isInvalidToken(err) {
+				callbackTokenFailures.Inc(action.name, "invalid")
+				logger.Log.Warnw("invalid callback token",
+					"action", action.name,
+					"chat_id", chatID,
+					"data", cb.Data,
+					"err", err,
+				)
+				text := ""
+				if action.invalid != nil {
+					text = action.invalid(h)
+				}
+				h.answerCallback(cb, text)
+				return
+			}
+			callbackTokenFailures.Inc(action.name, "error")
+			logger.Log.Errorw("failed to consume callback token",
+				"action", action.name,
+				"chat_id", chatID,
+				"err", err,
+			)
+			releaseCallback(ctx)
+			h.answerCallback(cb, "")
+			return
+		}
+
+		if action.owner != nil && action.owner(payload) != cb.From.ID {
+			ignoreForeign()
+			return
+		}
+
+		if consumed != nil {
+			ctx = withTokenRestore(ctx, h, consumed)
+		}
+
+		handler(ctx, cb, payload)
+	})
+}
+
+// peekOwner reads the owner of a stored token without consuming it. Errors
+// are left for decode, which reports them the same way.
+func (a CallbackAction[T]) peekOwner(
+	ctx context.Context,
+	h *Handler,
+	token string,
+) (int64, bool) {
+	var payload T
+	if err := h.callbackTokenService.Peek(ctx, token, a.name, &payload); err != nil {
+		return 0, false
+	}
+	return a.owner(payload), true
+}
+
+func (a CallbackAction[T]) decode(
+	ctx context.Context,
+	h *Handler,
+	token string,
+) (T, *domain.CallbackToken, error) {
+	var payload T
+
+	if a.signed == nil {
+		consumed, err := h.callbackTokenService.Consume(ctx, token, a.name, &payload)
+		return payload, consumed, err
+	}
+
+	fields, err := h.callbackSigner.Verify(a.name, token, time.Now())
+	switch {
+	case errors.Is(err, callbackdata.ErrExpired):
+		return payload, nil, apperr.Wrap(apperr.KindExpired, "callback_data.verify", err)
+	case err != nil:
+		return payload, nil, apperr.Wrap(apperr.KindInvalid, "callback_data.verify", err)
+	}
+
+	payload, ok := a.signed.decode(fields)
+	if !ok {
+		return payload, nil, apperr.Wrap(
+			apperr.KindInvalid,
+			"callback_data.decode",
+			callbackdata.ErrInvalid,
+		)
+	}
+	return payload, nil, nil
+}
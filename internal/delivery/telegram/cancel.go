@@ -2,7 +2,6 @@ package telegram
 
 import (
 	"context"
-	"strings"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/m4xvel/monetych_bot/internal/logger"
@@ -11,6 +10,7 @@ import (
 func (h *Handler) handlerCancelSelect(
 	ctx context.Context,
 	cb *tgbotapi.CallbackQuery,
+	payload CancelOrderSelectPayload,
 ) {
 	chatID := cb.Message.Chat.ID
 	h.answerCallback(cb, "")
@@ -19,52 +19,14 @@ func (h *Handler) handlerCancelSelect(
 		"chat_id", chatID,
 	)
 
-	parts := strings.Split(cb.Data, ":")
-	if len(parts) != 2 {
-		logger.Log.Warnw("invalid cancel callback data",
-			"chat_id", chatID,
-			"data", cb.Data,
-		)
-		return
-	}
-
-	tokenCallback := parts[1]
-
-	var payload CancelOrderSelectPayload
-
-	if err := h.callbackTokenService.Consume(
-		ctx,
-		tokenCallback,
-		"cancel",
-		&payload,
-	); err != nil {
-		if isInvalidToken(err) {
-			logger.Log.Warnw("invalid cancel callback token",
-				"chat_id", chatID,
-				"data", cb.Data,
-				"err", err,
-			)
-			return
-		}
-		logger.Log.Errorw("failed to consume cancel callback token",
-			"chat_id", chatID,
-			"err", err,
-		)
-		return
-	}
-
-	if payload.ChatID != cb.From.ID {
-		return
-	}
-
 	orderID := payload.OrderID
 
-	if err := h.callbackTokenService.DeleteByActionAndOrderID(
+	if err := h.invalidateCallbacks(
 		ctx,
-		"accept",
+		surfaceOrderRequest,
 		orderID,
 	); err != nil {
-		logger.Log.Errorw("failed to delete accept callbacks",
+		logger.Log.Errorw("failed to invalidate order request callbacks",
 			"order_id", orderID,
 			"err", err,
 		)
@@ -89,6 +51,7 @@ func (h *Handler) handlerCancelSelect(
 			"chat_id", chatID,
 			"err", err,
 		)
+		releaseCallback(ctx)
 		return
 	}
 
@@ -103,12 +66,8 @@ func (h *Handler) handlerCancelSelect(
 		h.text.YouHaveCancelledOrder,
 	)
 
-	if _, err := h.bot.Request(editText); err != nil {
-		wrapped := wrapTelegramErr("telegram.edit_cancel_message", err)
-		logger.Log.Errorw("failed to edit cancel message",
-			"order_id", orderID,
-			"chat_id", chatID,
-			"err", wrapped,
-		)
-	}
+	h.post(editText, "telegram.edit_cancel_message", nil,
+		"order_id", orderID,
+		"chat_id", chatID,
+	)
 }
@@ -30,10 +30,10 @@ func (h *Handler) handlerCatalogCommand(
 	createdTokens := make([]string, 0, len(games))
 	for _, g := range games {
 
-		token, err := h.callbackTokenService.Create(
+		token, err := gameAction.Create(
 			ctx,
-			"game",
-			&GameSelectPayload{
+			h,
+			GameSelectPayload{
 				GameID: g.ID,
 				ChatID: chatID,
 			},
@@ -49,7 +49,7 @@ func (h *Handler) handlerCatalogCommand(
 
 		btn := tgbotapi.NewInlineKeyboardButtonData(
 			g.Name,
-			"game:"+token,
+			gameAction.Data(token),
 		)
 		rows = append(rows, tgbotapi.NewInlineKeyboardRow(btn))
 	}
@@ -58,23 +58,21 @@ func (h *Handler) handlerCatalogCommand(
 	message.ParseMode = "markdown"
 	message.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
 
-	if _, err := h.bot.Send(message); err != nil {
-		wrapped := wrapTelegramErr("telegram.send_catalog", err)
-		logger.Log.Errorw("failed to send catalog message",
-			"chat_id", chatID,
-			"err", wrapped,
-		)
+	dropTokens := func(error) {
 		for _, token := range createdTokens {
-			if err := h.callbackTokenService.Delete(ctx, token, "game"); err != nil {
+			if err := gameAction.Delete(ctx, h, token); err != nil {
 				logger.Log.Errorw("failed to cleanup game callback token",
 					"chat_id", chatID,
 					"err", err,
 				)
 			}
 		}
-		return
 	}
 
+	h.post(message, "telegram.send_catalog", dropTokens,
+		"chat_id", chatID,
+	)
+
 	if err := h.stateService.SetStateIdle(ctx, chatID); err != nil {
 		logger.Log.Errorw("failed to set idle state after catalog command",
 			"chat_id", chatID,
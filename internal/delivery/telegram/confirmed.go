@@ -2,7 +2,6 @@ package telegram
 
 import (
 	"context"
-	"strings"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/m4xvel/monetych_bot/internal/logger"
@@ -11,6 +10,7 @@ import (
 func (h *Handler) handleConfirmedSelect(
 	ctx context.Context,
 	cb *tgbotapi.CallbackQuery,
+	payload ConfirmedAndDeclinedOrderSelectPayload,
 ) {
 	chatID := cb.Message.Chat.ID
 	messageID := cb.Message.MessageID
@@ -21,59 +21,25 @@ func (h *Handler) handleConfirmedSelect(
 		"callback_data", cb.Data,
 	)
 
-	parts := strings.Split(cb.Data, ":")
-	if len(parts) != 2 {
-		logger.Log.Warnw("invalid confirm callback data",
-			"chat_id", chatID,
-			"data", cb.Data,
-		)
-		return
-	}
-
-	tokenCallback := parts[1]
-
-	var payload ConfirmedAndDeclinedOrderSelectPayload
-
-	if err := h.callbackTokenService.Consume(
-		ctx,
-		tokenCallback,
-		"confirmed",
-		&payload,
-	); err != nil {
-		if isInvalidToken(err) {
-			logger.Log.Warnw("invalid confirmed callback token",
-				"chat_id", chatID,
-				"data", cb.Data,
-				"err", err,
-			)
-			return
-		}
-		logger.Log.Errorw("failed to consume confirmed callback token",
-			"chat_id", chatID,
-			"err", err,
-		)
-		return
-	}
-
 	orderID := payload.OrderID
 	topicID := payload.TopicID
 	threadID := payload.ThreadID
 
-	if err := h.callbackTokenService.DeleteByActionAndOrderID(
+	if err := h.invalidateCallbacks(
 		ctx,
-		"declined",
+		surfaceControlPanel,
 		orderID,
 	); err != nil {
-		logger.Log.Errorw("failed to delete declined callbacks",
+		logger.Log.Errorw("failed to invalidate control panel callbacks",
 			"order_id", orderID,
 			"err", err,
 		)
 	}
 
-	tokenReaffirm, err := h.callbackTokenService.Create(
+	tokenReaffirm, err := confirmedReaffirmAction.Create(
 		ctx,
-		"confirmed_reaffirm",
-		&ConfirmedAndDeclinedOrderSelectPayload{
+		h,
+		ConfirmedAndDeclinedOrderSelectPayload{
 			OrderID:  orderID,
 			TopicID:  topicID,
 			ThreadID: threadID,
@@ -88,13 +54,13 @@ func (h *Handler) handleConfirmedSelect(
 
 	btn := tgbotapi.NewInlineKeyboardButtonData(
 		h.text.AcceptText,
-		"confirmed_reaffirm:"+tokenReaffirm,
+		confirmedReaffirmAction.Data(tokenReaffirm),
 	)
 
-	tokenBack, err := h.callbackTokenService.Create(
+	tokenBack, err := backAction.Create(
 		ctx,
-		"back",
-		&ConfirmedAndDeclinedOrderSelectPayload{
+		h,
+		ConfirmedAndDeclinedOrderSelectPayload{
 			OrderID:  orderID,
 			TopicID:  topicID,
 			ThreadID: threadID,
@@ -109,7 +75,7 @@ func (h *Handler) handleConfirmedSelect(
 
 	btnBack := tgbotapi.NewInlineKeyboardButtonData(
 		h.text.BackButtonText,
-		"back:"+tokenBack,
+		backAction.Data(tokenBack),
 	)
 
 	markup := tgbotapi.NewInlineKeyboardMarkup(
@@ -124,30 +90,28 @@ func (h *Handler) handleConfirmedSelect(
 	)
 	editMessage.ReplyMarkup = &markup
 
-	if _, err := h.bot.Send(editMessage); err != nil {
-		wrapped := wrapTelegramErr("telegram.edit_confirm_confirmation", err)
-		logger.Log.Errorw("failed to edit confirmation message",
-			"chat_id", chatID,
-			"order_id", orderID,
-			"topic_id", topicID,
-			"err", wrapped,
-		)
-		if err := h.callbackTokenService.Delete(
+	dropTokens := func(error) {
+		if err := confirmedReaffirmAction.Delete(
 			ctx,
+			h,
 			tokenReaffirm,
-			"confirmed_reaffirm",
 		); err != nil {
 			logger.Log.Errorw("failed to cleanup confirmed reaffirm callback token",
 				"order_id", orderID,
 				"err", err,
 			)
 		}
-		if err := h.callbackTokenService.Delete(ctx, tokenBack, "back"); err != nil {
+		if err := backAction.Delete(ctx, h, tokenBack); err != nil {
 			logger.Log.Errorw("failed to cleanup back callback token",
 				"order_id", orderID,
 				"err", err,
 			)
 		}
-		return
 	}
+
+	h.post(editMessage, "telegram.edit_confirm_confirmation", dropTokens,
+		"chat_id", chatID,
+		"order_id", orderID,
+		"topic_id", topicID,
+	)
 }
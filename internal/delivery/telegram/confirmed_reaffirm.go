@@ -2,7 +2,6 @@ package telegram
 
 import (
 	"context"
-	"strings"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/m4xvel/monetych_bot/internal/logger"
@@ -11,6 +10,7 @@ import (
 func (h *Handler) handleConfirmedReaffirmSelect(
 	ctx context.Context,
 	cb *tgbotapi.CallbackQuery,
+	payload ConfirmedAndDeclinedOrderSelectPayload,
 ) {
 	chatID := cb.Message.Chat.ID
 	messageID := cb.Message.MessageID
@@ -21,50 +21,16 @@ func (h *Handler) handleConfirmedReaffirmSelect(
 		"callback_data", cb.Data,
 	)
 
-	parts := strings.Split(cb.Data, ":")
-	if len(parts) != 2 {
-		logger.Log.Warnw("invalid confirmed reaffirm callback data",
-			"chat_id", chatID,
-			"data", cb.Data,
-		)
-		return
-	}
-
-	tokenCallback := parts[1]
-
-	var payload ConfirmedAndDeclinedOrderSelectPayload
-
-	if err := h.callbackTokenService.Consume(
-		ctx,
-		tokenCallback,
-		"confirmed_reaffirm",
-		&payload,
-	); err != nil {
-		if isInvalidToken(err) {
-			logger.Log.Warnw("invalid confirmed reaffirm callback token",
-				"chat_id", chatID,
-				"data", cb.Data,
-				"err", err,
-			)
-			return
-		}
-		logger.Log.Errorw("failed to consume confirmed reaffirm callback token",
-			"chat_id", chatID,
-			"err", err,
-		)
-		return
-	}
-
 	orderID := payload.OrderID
 	topicID := payload.TopicID
 	threadID := payload.ThreadID
 
-	if err := h.callbackTokenService.DeleteByActionAndOrderID(
+	if err := h.invalidateCallbacks(
 		ctx,
-		"back",
+		surfaceControlPanel,
 		orderID,
 	); err != nil {
-		logger.Log.Errorw("failed to delete back callbacks",
+		logger.Log.Errorw("failed to invalidate control panel callbacks",
 			"order_id", orderID,
 			"err", err,
 		)
@@ -77,6 +43,7 @@ func (h *Handler) handleConfirmedReaffirmSelect(
 			"order_id", orderID,
 			"err", err,
 		)
+		releaseCallback(ctx)
 		return
 	}
 
@@ -94,6 +61,7 @@ func (h *Handler) handleConfirmedReaffirmSelect(
 			"order_id", orderID,
 			"err", err,
 		)
+		releaseCallback(ctx)
 		return
 	}
 
@@ -104,25 +72,18 @@ func (h *Handler) handleConfirmedReaffirmSelect(
 
 	msg := tgbotapi.NewMessage(topicID, h.text.YouConfirmedOrder)
 	msg.MessageThreadID = threadID
-	if _, err := h.bot.Send(msg); err != nil {
-		wrapped := wrapTelegramErr("telegram.send_expert_confirmed", err)
-		logger.Log.Errorw("failed to send expert confirmation message",
-			"chat_id", chatID,
-			"order_id", orderID,
-			"err", wrapped,
-		)
-	}
+	h.post(msg, "telegram.send_expert_confirmed", nil,
+		"chat_id", chatID,
+		"order_id", orderID,
+	)
 
-	if _, err := h.bot.Request(
+	h.post(
 		tgbotapi.NewDeleteTopicMessage(topicID, messageID, threadID),
-	); err != nil {
-		wrapped := wrapTelegramErr("telegram.delete_confirm_ui", err)
-		logger.Log.Errorw("failed to delete confirmation ui message",
-			"chat_id", chatID,
-			"order_id", orderID,
-			"err", wrapped,
-		)
-	}
+		"telegram.delete_confirm_ui",
+		nil,
+		"chat_id", chatID,
+		"order_id", orderID,
+	)
 
 	if err := h.orderMessageService.MarkDeletedByOrder(ctx, orderID); err != nil {
 		logger.Log.Errorw("failed to mark order messages deleted after expert confirmation",
@@ -131,10 +92,10 @@ func (h *Handler) handleConfirmedReaffirmSelect(
 		)
 	}
 
-	token, err := h.callbackTokenService.Create(
+	token, err := acceptClientAction.Create(
 		ctx,
-		"accept_client",
-		&CancelOrderSelectPayload{
+		h,
+		CancelOrderSelectPayload{
 			ChatID:  chatID,
 			OrderID: orderID,
 		},
@@ -149,23 +110,17 @@ func (h *Handler) handleConfirmedReaffirmSelect(
 	clientMsg := tgbotapi.NewMessage(order.UserChatID, h.text.ConfirmYourOrder)
 	btn := tgbotapi.NewInlineKeyboardButtonData(
 		h.text.AcceptText,
-		"accept_client:"+token,
+		acceptClientAction.Data(token),
 	)
 	clientMsg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(btn),
 	)
 
-	if _, err := h.bot.Send(clientMsg); err != nil {
-		wrapped := wrapTelegramErr("telegram.notify_client_confirm", err)
-		logger.Log.Errorw("failed to notify client about order confirmation",
-			"order_id", orderID,
-			"user_chat_id", order.UserChatID,
-			"err", wrapped,
-		)
-		if err := h.callbackTokenService.Delete(
+	dropToken := func(error) {
+		if err := acceptClientAction.Delete(
 			ctx,
+			h,
 			token,
-			"accept_client",
 		); err != nil {
 			logger.Log.Errorw("failed to cleanup accept client callback token",
 				"order_id", orderID,
@@ -173,4 +128,9 @@ func (h *Handler) handleConfirmedReaffirmSelect(
 			)
 		}
 	}
+
+	h.post(clientMsg, "telegram.notify_client_confirm", dropToken,
+		"order_id", orderID,
+		"user_chat_id", order.UserChatID,
+	)
 }
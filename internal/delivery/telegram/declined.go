@@ -2,7 +2,6 @@ package telegram
 
 import (
 	"context"
-	"strings"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/m4xvel/monetych_bot/internal/logger"
@@ -11,6 +10,7 @@ import (
 func (h *Handler) handleDeclinedSelect(
 	ctx context.Context,
 	cb *tgbotapi.CallbackQuery,
+	payload ConfirmedAndDeclinedOrderSelectPayload,
 ) {
 	messageID := cb.Message.MessageID
 	h.answerCallback(cb, "")
@@ -19,47 +19,16 @@ func (h *Handler) handleDeclinedSelect(
 		"callback_data", cb.Data,
 	)
 
-	parts := strings.Split(cb.Data, ":")
-	if len(parts) != 2 {
-		logger.Log.Warnw("invalid declined callback data",
-			"data", cb.Data,
-		)
-		return
-	}
-
-	tokenCallback := parts[1]
-
-	var payload ConfirmedAndDeclinedOrderSelectPayload
-
-	if err := h.callbackTokenService.Consume(
-		ctx,
-		tokenCallback,
-		"declined",
-		&payload,
-	); err != nil {
-		if isInvalidToken(err) {
-			logger.Log.Warnw("invalid declined callback token",
-				"data", cb.Data,
-				"err", err,
-			)
-			return
-		}
-		logger.Log.Errorw("failed to consume declined callback token",
-			"err", err,
-		)
-		return
-	}
-
 	orderID := payload.OrderID
 	topicID := payload.TopicID
 	threadID := payload.ThreadID
 
-	if err := h.callbackTokenService.DeleteByActionAndOrderID(
+	if err := h.invalidateCallbacks(
 		ctx,
-		"confirmed",
+		surfaceControlPanel,
 		orderID,
 	); err != nil {
-		logger.Log.Errorw("failed to delete confirmed callbacks",
+		logger.Log.Errorw("failed to invalidate control panel callbacks",
 			"order_id", orderID,
 			"err", err,
 		)
@@ -71,10 +40,10 @@ func (h *Handler) handleDeclinedSelect(
 		"thread_id", threadID,
 	)
 
-	tokenReaffirm, err := h.callbackTokenService.Create(
+	tokenReaffirm, err := declinedReaffirmAction.Create(
 		ctx,
-		"declined_reaffirm",
-		&ConfirmedAndDeclinedOrderSelectPayload{
+		h,
+		ConfirmedAndDeclinedOrderSelectPayload{
 			OrderID:  orderID,
 			TopicID:  topicID,
 			ThreadID: threadID,
@@ -89,13 +58,13 @@ func (h *Handler) handleDeclinedSelect(
 
 	btn := tgbotapi.NewInlineKeyboardButtonData(
 		h.text.AcceptText,
-		"declined_reaffirm:"+tokenReaffirm,
+		declinedReaffirmAction.Data(tokenReaffirm),
 	)
 
-	tokenBack, err := h.callbackTokenService.Create(
+	tokenBack, err := backAction.Create(
 		ctx,
-		"back",
-		&ConfirmedAndDeclinedOrderSelectPayload{
+		h,
+		ConfirmedAndDeclinedOrderSelectPayload{
 			OrderID:  orderID,
 			TopicID:  topicID,
 			ThreadID: threadID,
@@ -110,7 +79,7 @@ func (h *Handler) handleDeclinedSelect(
 
 	btnBack := tgbotapi.NewInlineKeyboardButtonData(
 		h.text.BackButtonText,
-		"back:"+tokenBack,
+		backAction.Data(tokenBack),
 	)
 
 	markup := tgbotapi.NewInlineKeyboardMarkup(
@@ -125,28 +94,27 @@ func (h *Handler) handleDeclinedSelect(
 	)
 	editMessage.ReplyMarkup = &markup
 
-	if _, err := h.bot.Send(editMessage); err != nil {
-		wrapped := wrapTelegramErr("telegram.edit_decline_confirmation", err)
-		logger.Log.Errorw("failed to edit decline confirmation message",
-			"order_id", orderID,
-			"topic_id", topicID,
-			"err", wrapped,
-		)
-		if err := h.callbackTokenService.Delete(
+	dropTokens := func(error) {
+		if err := declinedReaffirmAction.Delete(
 			ctx,
+			h,
 			tokenReaffirm,
-			"declined_reaffirm",
 		); err != nil {
 			logger.Log.Errorw("failed to cleanup declined reaffirm callback token",
 				"order_id", orderID,
 				"err", err,
 			)
 		}
-		if err := h.callbackTokenService.Delete(ctx, tokenBack, "back"); err != nil {
+		if err := backAction.Delete(ctx, h, tokenBack); err != nil {
 			logger.Log.Errorw("failed to cleanup back callback token",
 				"order_id", orderID,
 				"err", err,
 			)
 		}
 	}
+
+	h.post(editMessage, "telegram.edit_decline_confirmation", dropTokens,
+		"order_id", orderID,
+		"topic_id", topicID,
+	)
 }
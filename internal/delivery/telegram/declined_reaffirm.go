@@ -2,7 +2,6 @@ package telegram
 
 import (
 	"context"
-	"strings"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/m4xvel/monetych_bot/internal/logger"
@@ -11,6 +10,7 @@ import (
 func (h *Handler) handleDeclinedReaffirmSelect(
 	ctx context.Context,
 	cb *tgbotapi.CallbackQuery,
+	payload ConfirmedAndDeclinedOrderSelectPayload,
 ) {
 	messageID := cb.Message.MessageID
 	h.answerCallback(cb, "")
@@ -19,47 +19,16 @@ func (h *Handler) handleDeclinedReaffirmSelect(
 		"callback_data", cb.Data,
 	)
 
-	parts := strings.Split(cb.Data, ":")
-	if len(parts) != 2 {
-		logger.Log.Warnw("invalid declined reaffirm callback data",
-			"data", cb.Data,
-		)
-		return
-	}
-
-	tokenCallback := parts[1]
-
-	var payload ConfirmedAndDeclinedOrderSelectPayload
-
-	if err := h.callbackTokenService.Consume(
-		ctx,
-		tokenCallback,
-		"declined_reaffirm",
-		&payload,
-	); err != nil {
-		if isInvalidToken(err) {
-			logger.Log.Warnw("invalid declined reaffirm callback token",
-				"data", cb.Data,
-				"err", err,
-			)
-			return
-		}
-		logger.Log.Errorw("failed to consume declined reaffirm callback token",
-			"err", err,
-		)
-		return
-	}
-
 	orderID := payload.OrderID
 	topicID := payload.TopicID
 	threadID := payload.ThreadID
 
-	if err := h.callbackTokenService.DeleteByActionAndOrderID(
+	if err := h.invalidateCallbacks(
 		ctx,
-		"back",
+		surfaceControlPanel,
 		orderID,
 	); err != nil {
-		logger.Log.Errorw("failed to delete back callbacks",
+		logger.Log.Errorw("failed to invalidate control panel callbacks",
 			"order_id", orderID,
 			"err", err,
 		)
@@ -70,6 +39,7 @@ func (h *Handler) handleDeclinedReaffirmSelect(
 		logger.Log.Errorw("failed to get order for decline reaffirm",
 			"order_id", orderID,
 		)
+		releaseCallback(ctx)
 		return
 	}
 
@@ -85,6 +55,7 @@ func (h *Handler) handleDeclinedReaffirmSelect(
 			"order_id", orderID,
 			"err", err,
 		)
+		releaseCallback(ctx)
 		return
 	}
 
@@ -94,23 +65,18 @@ func (h *Handler) handleDeclinedReaffirmSelect(
 
 	msg := tgbotapi.NewMessage(topicID, h.text.YouHaveCancelledOrder)
 	msg.MessageThreadID = threadID
-	if _, err := h.bot.Send(msg); err != nil {
-		wrapped := wrapTelegramErr("telegram.send_decline_notice", err)
-		logger.Log.Errorw("failed to send decline notice",
-			"order_id", orderID,
-			"topic_id", topicID,
-			"err", wrapped,
-		)
-	}
+	h.post(msg, "telegram.send_decline_notice", nil,
+		"order_id", orderID,
+		"topic_id", topicID,
+	)
 
-	if _, err := h.bot.Request(tgbotapi.NewDeleteTopicMessage(topicID, messageID, threadID)); err != nil {
-		wrapped := wrapTelegramErr("telegram.delete_decline_ui", err)
-		logger.Log.Errorw("failed to delete decline confirmation message",
-			"order_id", orderID,
-			"topic_id", topicID,
-			"err", wrapped,
-		)
-	}
+	h.post(
+		tgbotapi.NewDeleteTopicMessage(topicID, messageID, threadID),
+		"telegram.delete_decline_ui",
+		nil,
+		"order_id", orderID,
+		"topic_id", topicID,
+	)
 
 	if err := h.orderMessageService.MarkDeletedByOrder(ctx, orderID); err != nil {
 		logger.Log.Errorw("failed to mark order messages deleted after decline",
@@ -118,14 +84,13 @@ func (h *Handler) handleDeclinedReaffirmSelect(
 			"err", err,
 		)
 	}
-	if _, err := h.bot.Send(tgbotapi.NewMessage(order.UserChatID, h.text.YouOrderCancelled)); err != nil {
-		wrapped := wrapTelegramErr("telegram.notify_user_declined", err)
-		logger.Log.Errorw("failed to notify user about declined order",
-			"order_id", orderID,
-			"user_chat_id", order.UserChatID,
-			"err", wrapped,
-		)
-	}
+	h.post(
+		tgbotapi.NewMessage(order.UserChatID, h.text.YouOrderCancelled),
+		"telegram.notify_user_declined",
+		nil,
+		"order_id", orderID,
+		"user_chat_id", order.UserChatID,
+	)
 
 	if err := h.stateService.SetStateIdle(ctx, order.UserChatID); err != nil {
 		logger.Log.Errorw("failed to set user idle after decline",
@@ -0,0 +1,123 @@
+package telegram
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/m4xvel/monetych_bot/internal/domain"
+	"github.com/m4xvel/monetych_bot/internal/logger"
+)
+
+// DedupStore remembers recently handled callbacks. Acquire reports whether
+// the key is new and marks it; Release forgets it so the user can retry.
+// Replace the in-memory store with a shared one when running several
+// replicas.
+type DedupStore interface {
+	Acquire(key string) bool
+	Release(key string)
+}
+
+type dedupEntry struct {
+	key     string
+	expires time.Time
+}
+
+type MemoryDedupStore struct {
+	ttl        time.Duration
+	maxEntries int
+
+	mu      sync.Mutex
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+func NewMemoryDedupStore(ttl time.Duration, maxEntries int) *MemoryDedupStore {
+	return &MemoryDedupStore{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+	}
+}
+
+func (s *MemoryDedupStore) Acquire(key string) bool {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.evictExpired(now)
+
+	if _, exists := s.entries[key]; exists {
+		return false
+	}
+
+	for s.maxEntries > 0 && len(s.entries) >= s.maxEntries {
+		s.remove(s.order.Front())
+	}
+
+	s.entries[key] = s.order.PushBack(&dedupEntry{
+		key:     key,
+		expires: now.Add(s.ttl),
+	})
+
+	return true
+}
+
+func (s *MemoryDedupStore) Release(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, ok := s.entries[key]; ok {
+		s.remove(el)
+	}
+}
+
+// Entries are appended with the same TTL, so the list is ordered by expiry.
+func (s *MemoryDedupStore) evictExpired(now time.Time) {
+	for el := s.order.Front(); el != nil; el = s.order.Front() {
+		if el.Value.(*dedupEntry).expires.After(now) {
+			return
+		}
+		s.remove(el)
+	}
+}
+
+func (s *MemoryDedupStore) remove(el *list.Element) {
+	entry := s.order.Remove(el).(*dedupEntry)
+	delete(s.entries, entry.key)
+}
+
+type callbackReleaseKey struct{}
+
+// releaseCallback lets a callback handler give up its dedup slot after a
+// transient failure, so pressing the button again is not ignored.
+func releaseCallback(ctx context.Context) {
+	if release, ok := ctx.Value(callbackReleaseKey{}).(func()); ok {
+		release()
+	}
+}
+
+// withTokenRestore makes releaseCallback also put the consumed token back;
+// without it the retried press would only get the "already used" answer.
+func withTokenRestore(
+	ctx context.Context,
+	h *Handler,
+	consumed *domain.CallbackToken,
+) context.Context {
+	release, _ := ctx.Value(callbackReleaseKey{}).(func())
+
+	return context.WithValue(ctx, callbackReleaseKey{}, func() {
+		if err := h.callbackTokenService.Restore(ctx, consumed); err != nil {
+			logger.Log.Errorw("failed to restore callback token",
+				"action", consumed.Action,
+				"err", err,
+			)
+		}
+		if release != nil {
+			release()
+		}
+	})
+}
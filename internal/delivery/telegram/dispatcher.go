@@ -0,0 +1,143 @@
+package telegram
+
+import (
+	"context"
+	"sync"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/m4xvel/monetych_bot/internal/logger"
+)
+
+type UpdateFunc func(ctx context.Context, upd tgbotapi.Update)
+
+// UpdateDispatcher pins every chat to one worker so updates from the same
+// chat are handled in arrival order while different chats run in parallel.
+type UpdateDispatcher struct {
+	queues []chan tgbotapi.Update
+	handle UpdateFunc
+	wg     sync.WaitGroup
+}
+
+func NewUpdateDispatcher(
+	workers int,
+	queueSize int,
+	handle UpdateFunc,
+) *UpdateDispatcher {
+	d := &UpdateDispatcher{
+		queues: make([]chan tgbotapi.Update, workers),
+		handle: handle,
+	}
+	for i := range d.queues {
+		d.queues[i] = make(chan tgbotapi.Update, queueSize)
+	}
+	return d
+}
+
+func (d *UpdateDispatcher) Start(ctx context.Context) {
+	for i, q := range d.queues {
+		d.wg.Add(1)
+		go d.runWorker(ctx, i, q)
+	}
+
+	logger.Log.Infow("update dispatcher started",
+		"workers", len(d.queues),
+		"queue_size", cap(d.queues[0]),
+	)
+}
+
+// Dispatch blocks while the target worker queue is full, which slows down
+// update intake instead of growing memory without bound.
+func (d *UpdateDispatcher) Dispatch(ctx context.Context, upd tgbotapi.Update) bool {
+	idx := d.workerFor(upd)
+	q := d.queues[idx]
+
+	select {
+	case q <- upd:
+		return true
+	default:
+	}
+
+	logger.Log.Warnw("update queue full, applying backpressure",
+		"worker", idx,
+		"update_id", upd.UpdateID,
+	)
+
+	select {
+	case q <- upd:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// Stop closes the queues and waits until workers have drained them or ctx
+// expires. It reports whether every queued update was handled.
+func (d *UpdateDispatcher) Stop(ctx context.Context) bool {
+	for _, q := range d.queues {
+		close(q)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (d *UpdateDispatcher) workerFor(upd tgbotapi.Update) int {
+	chatID, ok := updateChatID(upd)
+	if !ok {
+		return 0
+	}
+
+	key := uint64(chatID)
+	key ^= key >> 33
+	key *= 0xff51afd7ed558ccd
+	key ^= key >> 33
+
+	return int(key % uint64(len(d.queues)))
+}
+
+func (d *UpdateDispatcher) runWorker(
+	ctx context.Context,
+	idx int,
+	q <-chan tgbotapi.Update,
+) {
+	defer d.wg.Done()
+
+	for upd := range q {
+		d.process(ctx, idx, upd)
+	}
+}
+
+func (d *UpdateDispatcher) process(
+	ctx context.Context,
+	idx int,
+	upd tgbotapi.Update,
+) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Log.Errorw("panic in update handler",
+				"worker", idx,
+				"update_id", upd.UpdateID,
+				"panic", r,
+			)
+		}
+	}()
+
+	d.handle(ctx, upd)
+}
+
+func updateChatID(upd tgbotapi.Update) (int64, bool) {
+	if upd.MyChatMember != nil {
+		return upd.MyChatMember.Chat.ID, true
+	}
+	return extractChatID(upd)
+}
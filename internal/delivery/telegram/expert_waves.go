@@ -0,0 +1,74 @@
+package telegram
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// expertWaves holds the notifications postponed while the best ranked
+// experts have a head start on a new order.
+type expertWaves struct {
+	mu      sync.Mutex
+	pending map[int]*pendingWave
+}
+
+type pendingWave struct {
+	timer *time.Timer
+	run   func(ctx context.Context)
+}
+
+func newExpertWaves() *expertWaves {
+	return &expertWaves{
+		pending: make(map[int]*pendingWave),
+	}
+}
+
+func (w *expertWaves) schedule(
+	orderID int,
+	delay time.Duration,
+	run func(ctx context.Context),
+) {
+	wave := &pendingWave{run: run}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	wave.timer = time.AfterFunc(delay, func() {
+		if w.take(orderID) != nil {
+			run(context.Background())
+		}
+	})
+	w.pending[orderID] = wave
+}
+
+func (w *expertWaves) take(orderID int) *pendingWave {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	wave := w.pending[orderID]
+	delete(w.pending, orderID)
+	return wave
+}
+
+// cancel drops the postponed wave of an order that is no longer new.
+func (w *expertWaves) cancel(orderID int) {
+	if wave := w.take(orderID); wave != nil {
+		wave.timer.Stop()
+	}
+}
+
+// flush runs every postponed wave right away, so a restart doesn't leave
+// the remaining experts unaware of an order.
+func (w *expertWaves) flush(ctx context.Context) int {
+	w.mu.Lock()
+	waves := w.pending
+	w.pending = make(map[int]*pendingWave)
+	w.mu.Unlock()
+
+	for _, wave := range waves {
+		wave.timer.Stop()
+		wave.run(ctx)
+	}
+	return len(waves)
+}
@@ -0,0 +1,112 @@
+package telegram
+
+import (
+	"context"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/m4xvel/monetych_bot/internal/logger"
+	"github.com/m4xvel/monetych_bot/internal/ratelimit"
+)
+
+func (h *Handler) floodGuard(
+	ctx context.Context,
+	uc *UpdateContext,
+) bool {
+	if h.flood == nil || uc.Update.Message == nil || uc.Role == RoleSupport {
+		return true
+	}
+
+	msg := uc.Update.Message
+	if msg.From != nil && msg.From.IsBot {
+		return true
+	}
+
+	chatID := uc.ChatID
+
+	// All orders of an expert run as threads of one forum, so the bucket
+	// follows the sender rather than the chat. In private chats they match.
+	senderID := chatID
+	if msg.From != nil {
+		senderID = msg.From.ID
+	}
+
+	role := ratelimit.RoleUser
+	if uc.Role == RoleExpert {
+		role = ratelimit.RoleExpert
+	}
+
+	res := h.flood.Check(senderID, role, time.Now())
+
+	switch res.Verdict {
+	case ratelimit.Allowed:
+		return true
+
+	case ratelimit.Throttled:
+		logger.Log.Warnw("message throttled by flood guard",
+			"chat_id", chatID,
+			"sender_id", senderID,
+			"role", role,
+		)
+		if res.Warn {
+			h.replyFloodNotice(msg, h.text.FloodWarningText)
+		}
+
+	case ratelimit.Muted:
+		if !res.JustMuted {
+			return false
+		}
+
+		minutes := int(time.Until(res.MutedUntil).Round(time.Minute).Minutes())
+
+		logger.Log.Warnw("sender muted by flood guard",
+			"chat_id", chatID,
+			"sender_id", senderID,
+			"role", role,
+			"muted_until", res.MutedUntil,
+		)
+
+		h.replyFloodNotice(msg, h.textDynamic.FloodMutedText(minutes))
+		h.notifySupport(
+			h.textDynamic.FloodMutedAlert(senderID, string(role), minutes),
+			"telegram.send_flood_alert",
+			"chat_id", chatID,
+			"sender_id", senderID,
+		)
+	}
+
+	return false
+}
+
+func (h *Handler) isExpertChat(chatID int64) bool {
+	experts, err := h.expertService.GetAllExperts()
+	if err != nil {
+		return false
+	}
+
+	for _, e := range experts {
+		if e.TopicID == chatID {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (h *Handler) replyFloodNotice(msg *tgbotapi.Message, text string) {
+	reply := tgbotapi.NewMessage(msg.Chat.ID, text)
+	reply.MessageThreadID = msg.MessageThreadID
+
+	h.post(reply, "telegram.send_flood_notice", nil,
+		"chat_id", msg.Chat.ID,
+	)
+}
+
+func (h *Handler) notifySupport(text string, op string, fields ...any) {
+	support := h.supportService.GetSupport()
+	if support.ChatID == 0 {
+		return
+	}
+
+	h.post(tgbotapi.NewMessage(support.ChatID, text), op, nil, fields...)
+}
@@ -0,0 +1,123 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/m4xvel/monetych_bot/internal/domain"
+	"github.com/m4xvel/monetych_bot/internal/fraud"
+	"github.com/m4xvel/monetych_bot/internal/logger"
+)
+
+func (h *Handler) applyFraudActions(
+	ctx context.Context,
+	orderID int,
+	role domain.SenderRole,
+	msg *tgbotapi.Message,
+	hits []fraud.Hit,
+) {
+	if len(hits) == 0 {
+		return
+	}
+
+	blocked := fraud.HasAction(hits, fraud.ActionBlock)
+
+	switch {
+	case blocked:
+		h.replyFraudNotice(msg, h.text.FraudBlockedText)
+	case fraud.HasAction(hits, fraud.ActionWarn):
+		h.replyFraudNotice(msg, h.text.FraudWarningText)
+	}
+
+	if fraud.HasAction(hits, fraud.ActionAlert) ||
+		(blocked && role == domain.SenderExpert) {
+		h.alertSupportAboutFraud(ctx, orderID, role, hits)
+	}
+}
+
+func (h *Handler) replyFraudNotice(msg *tgbotapi.Message, text string) {
+	reply := tgbotapi.NewMessage(msg.Chat.ID, text)
+	reply.ReplyToMessageID = msg.MessageID
+	reply.MessageThreadID = msg.MessageThreadID
+
+	h.post(reply, "telegram.send_fraud_notice", nil,
+		"chat_id", msg.Chat.ID,
+	)
+}
+
+func (h *Handler) alertSupportAboutFraud(
+	ctx context.Context,
+	orderID int,
+	role domain.SenderRole,
+	hits []fraud.Hit,
+) {
+	support := h.supportService.GetSupport()
+	if support.ChatID == 0 {
+		return
+	}
+
+	token := ""
+	if order, err := h.orderService.GetOrderByID(ctx, orderID); err == nil && order != nil {
+		token = order.Token
+	} else {
+		logger.Log.Warnw("failed to get order for fraud alert",
+			"order_id", orderID,
+			"err", err,
+		)
+	}
+
+	rules := make([]string, 0, len(hits))
+	for _, hit := range hits {
+		rules = append(rules, fmt.Sprintf("%s (%s)", hit.Rule, hit.Action))
+	}
+
+	message := tgbotapi.NewMessage(
+		support.ChatID,
+		h.textDynamic.FraudAlert(
+			orderID,
+			token,
+			h.senderLabel(role),
+			strings.Join(rules, ", "),
+		),
+	)
+
+	h.post(message, "telegram.send_fraud_alert", nil,
+		"order_id", orderID,
+	)
+}
+
+func (h *Handler) formatFraudHits(hits []domain.FraudHit) string {
+	if len(hits) == 0 {
+		return ""
+	}
+
+	var builder strings.Builder
+
+	builder.WriteString("\n")
+	builder.WriteString(h.text.SearchFraudHeader)
+	for _, hit := range hits {
+		builder.WriteString(fmt.Sprintf(
+			h.text.SearchFraudLineTemplate,
+			hit.CreatedAt.Format("02.01 15:04"),
+			h.senderLabel(hit.SenderRole),
+			html.EscapeString(hit.Rule),
+			html.EscapeString(hit.Action),
+		))
+	}
+
+	return builder.String()
+}
+
+func (h *Handler) senderLabel(role domain.SenderRole) string {
+	switch role {
+	case domain.SenderUser:
+		return h.text.SenderUserLabel
+	case domain.SenderExpert:
+		return h.text.SenderExpertLabel
+	default:
+		return h.text.SenderSystemLabel
+	}
+}
@@ -3,7 +3,6 @@ package telegram
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/m4xvel/monetych_bot/internal/logger"
@@ -12,6 +11,7 @@ import (
 func (h *Handler) handleGameSelect(
 	ctx context.Context,
 	cb *tgbotapi.CallbackQuery,
+	payload GameSelectPayload,
 ) {
 	chatID := cb.Message.Chat.ID
 	h.answerCallback(cb, "")
@@ -20,44 +20,6 @@ func (h *Handler) handleGameSelect(
 		"chat_id", chatID,
 	)
 
-	parts := strings.Split(cb.Data, ":")
-	if len(parts) != 2 {
-		logger.Log.Warnw("invalid game callback data",
-			"chat_id", chatID,
-			"data", cb.Data,
-		)
-		return
-	}
-
-	token := parts[1]
-
-	var payload GameSelectPayload
-
-	if err := h.callbackTokenService.Consume(
-		ctx,
-		token,
-		"game",
-		&payload,
-	); err != nil {
-		if isInvalidToken(err) {
-			logger.Log.Warnw("invalid game callback token",
-				"chat_id", chatID,
-				"data", cb.Data,
-				"err", err,
-			)
-			return
-		}
-		logger.Log.Errorw("failed to consume game callback token",
-			"chat_id", chatID,
-			"err", err,
-		)
-		return
-	}
-
-	if payload.ChatID != cb.From.ID {
-		return
-	}
-
 	gameID := payload.GameID
 
 	game, err := h.gameService.GetGameByID(gameID)
@@ -82,10 +44,10 @@ func (h *Handler) handleGameSelect(
 	var rows [][]tgbotapi.InlineKeyboardButton
 	createdTokens := make([]string, 0, len(types))
 	for _, t := range types {
-		token, err := h.callbackTokenService.Create(
+		token, err := typeAction.Create(
 			ctx,
-			"type",
-			&TypeSelectPayload{
+			h,
+			TypeSelectPayload{
 				ChatID: chatID,
 				GameID: game.ID,
 				TypeID: t.ID,
@@ -102,7 +64,7 @@ func (h *Handler) handleGameSelect(
 
 		btn := tgbotapi.NewInlineKeyboardButtonData(
 			t.Name,
-			"type:"+token,
+			typeAction.Data(token),
 		)
 
 		rows = append(rows, tgbotapi.NewInlineKeyboardRow(btn))
@@ -125,14 +87,9 @@ func (h *Handler) handleGameSelect(
 	markup := tgbotapi.NewInlineKeyboardMarkup(rows...)
 	edit.ReplyMarkup = &markup
 
-	if _, err := h.bot.Request(edit); err != nil {
-		wrapped := wrapTelegramErr("telegram.edit_game_message", err)
-		logger.Log.Errorw("failed to edit game selection message",
-			"chat_id", chatID,
-			"err", wrapped,
-		)
+	dropTokens := func(error) {
 		for _, token := range createdTokens {
-			if err := h.callbackTokenService.Delete(ctx, token, "type"); err != nil {
+			if err := typeAction.Delete(ctx, h, token); err != nil {
 				logger.Log.Errorw("failed to cleanup type callback token",
 					"chat_id", chatID,
 					"err", err,
@@ -140,4 +97,8 @@ func (h *Handler) handleGameSelect(
 			}
 		}
 	}
+
+	h.post(edit, "telegram.edit_game_message", dropTokens,
+		"chat_id", chatID,
+	)
 }
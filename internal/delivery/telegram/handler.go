@@ -3,11 +3,15 @@ package telegram
 import (
 	"context"
 	"strings"
+	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/m4xvel/monetych_bot/internal/callbackdata"
 	"github.com/m4xvel/monetych_bot/internal/domain"
 	"github.com/m4xvel/monetych_bot/internal/features"
 	"github.com/m4xvel/monetych_bot/internal/logger"
+	"github.com/m4xvel/monetych_bot/internal/ratelimit"
+	"github.com/m4xvel/monetych_bot/internal/redact"
 	"github.com/m4xvel/monetych_bot/internal/usecase"
 	"github.com/m4xvel/monetych_bot/pkg/utils"
 )
@@ -79,14 +83,30 @@ type Handler struct {
 	gameService                  *usecase.GameService
 	orderService                 *usecase.OrderService
 	expertService                *usecase.ExpertService
+	expertRatingService          *usecase.ExpertRatingService
 	supportService               *usecase.SupportService
 	orderMessageService          *usecase.OrderMessageService
 	orderChatMessageService      *usecase.OrderChatMessageService
 	reviewService                *usecase.ReviewService
 	callbackTokenService         *usecase.CallbackTokenService
 	userPolicyAcceptancesService *usecase.UserPolicyAcceptancesService
+	fraudHitService              *usecase.FraudHitService
+	responseTemplateService      *usecase.ResponseTemplateService
+	outboxService                *usecase.OutboxService
 	verificationEnabled          bool
-	copyMessageQueue             *sendQueue
+	notifyReviewAuthors          bool
+	reviewsChannelID             int64
+	orderPauseGrace              time.Duration
+	reviewReminderDelay          time.Duration
+	reviewAutoPublishAfter       time.Duration
+	expertHeadStart              time.Duration
+	expertFirstWave              int
+	expertWaves                  *expertWaves
+	redactor                     *redact.Pipeline
+	flood                        *ratelimit.FloodGuard
+	callbackSigner               *callbackdata.Signer
+	sendQueue                    *sendScheduler
+	outbox                       *outboxDispatcher
 	router                       *Router
 	feature                      *features.Features
 	text                         *utils.Messages
@@ -100,13 +120,28 @@ func NewHandler(
 	gs *usecase.GameService,
 	os *usecase.OrderService,
 	es *usecase.ExpertService,
+	ers *usecase.ExpertRatingService,
 	sups *usecase.SupportService,
 	rs *usecase.ReviewService,
 	oms *usecase.OrderMessageService,
 	ocms *usecase.OrderChatMessageService,
 	cts *usecase.CallbackTokenService,
 	upa *usecase.UserPolicyAcceptancesService,
+	fhs *usecase.FraudHitService,
+	rts *usecase.ResponseTemplateService,
+	obs *usecase.OutboxService,
 	verificationEnabled bool,
+	notifyReviewAuthors bool,
+	reviewsChannelID int64,
+	orderPauseGrace time.Duration,
+	reviewReminderDelay time.Duration,
+	reviewAutoPublishAfter time.Duration,
+	expertHeadStart time.Duration,
+	expertFirstWave int,
+	redactor *redact.Pipeline,
+	floodGuard *ratelimit.FloodGuard,
+	callbackDedup DedupStore,
+	callbackSigner *callbackdata.Signer,
 	privacyPolicyURL string,
 	publicOfferURL string,
 ) *Handler {
@@ -117,15 +152,31 @@ func NewHandler(
 		gameService:                  gs,
 		orderService:                 os,
 		expertService:                es,
+		expertRatingService:          ers,
 		supportService:               sups,
 		reviewService:                rs,
 		orderMessageService:          oms,
 		orderChatMessageService:      ocms,
 		callbackTokenService:         cts,
 		userPolicyAcceptancesService: upa,
+		fraudHitService:              fhs,
+		responseTemplateService:      rts,
+		outboxService:                obs,
 		verificationEnabled:          verificationEnabled,
-		copyMessageQueue:             newSendQueue(copyMessageQueueSize),
-		router:                       NewRouter(),
+		notifyReviewAuthors:          notifyReviewAuthors,
+		reviewsChannelID:             reviewsChannelID,
+		orderPauseGrace:              orderPauseGrace,
+		reviewReminderDelay:          reviewReminderDelay,
+		reviewAutoPublishAfter:       reviewAutoPublishAfter,
+		expertHeadStart:              expertHeadStart,
+		expertFirstWave:              expertFirstWave,
+		expertWaves:                  newExpertWaves(),
+		redactor:                     redactor,
+		flood:                        floodGuard,
+		callbackSigner:               callbackSigner,
+		sendQueue:                    newSendScheduler(),
+		outbox:                       newOutboxDispatcher(),
+		router:                       NewRouter(callbackDedup),
 		feature:                      features.NewFeatures(),
 		text:                         utils.NewMessages(privacyPolicyURL, publicOfferURL),
 		textDynamic:                  utils.NewDynamic(privacyPolicyURL, publicOfferURL),
@@ -136,59 +187,66 @@ func NewHandler(
 }
 
 func (h *Handler) registerRoutes() {
+	h.router.Use(
+		RecoverMiddleware,
+		h.router.MetricsMiddleware,
+		LoggingMiddleware,
+		h.resolveMiddleware,
+		Guard("flood guard", h.floodGuard),
+		Guard("expert guard", h.expertGuard),
+		Guard("support guard", h.supportGuard),
+		Guard("state guard", h.stateGuard),
+		Guard("start guard", h.startGuard),
+	)
+
 	h.router.RegisterCommand("start", h.handleStartCommand)
 	h.router.RegisterCommand("catalog", h.handlerCatalogCommand)
 	h.router.RegisterCommand("support", h.handlerSupportCommand)
+	h.router.RegisterCommand("reviews", h.handleReviewsCommand)
 	h.router.RegisterCommand("search", h.supportOnly(h.SearchCommand))
-
-	h.router.RegisterCallback("accept_privacy", h.handleAcceptPrivacySelect)
-	h.router.RegisterCallback("game:", h.handleGameSelect)
-	h.router.RegisterCallback("type:", h.handleTypeSelect)
-	h.router.RegisterCallback("order:", h.handleOrderSelect)
-	h.router.RegisterCallback("accept:", h.handleAcceptSelect)
-	h.router.RegisterCallback("cancel:", h.handlerCancelSelect)
-	h.router.RegisterCallback("declined:", h.handleDeclinedSelect)
-	h.router.RegisterCallback("declined_reaffirm:",
-		h.handleDeclinedReaffirmSelect,
-	)
-	h.router.RegisterCallback("confirmed:", h.handleConfirmedSelect)
-	h.router.RegisterCallback("confirmed_reaffirm:",
-		h.handleConfirmedReaffirmSelect,
-	)
+	h.router.RegisterCommand("outbox", h.supportOnly(h.OutboxCommand))
+	h.router.RegisterCommand("moderation", h.supportOnly(h.ModerationCommand))
+	h.router.RegisterCommand("tpl", h.handleTemplateCommand)
+
+	RegisterTyped(h, acceptPrivacyAction, h.handleAcceptPrivacySelect)
+	RegisterTyped(h, gameAction, h.handleGameSelect)
+	RegisterTyped(h, typeAction, h.handleTypeSelect)
+	RegisterTyped(h, orderAction, h.handleOrderSelect)
+	RegisterTyped(h, acceptAction, h.handleAcceptSelect)
+	RegisterTyped(h, cancelAction, h.handlerCancelSelect)
+	RegisterTyped(h, declinedAction, h.handleDeclinedSelect)
+	RegisterTyped(h, declinedReaffirmAction, h.handleDeclinedReaffirmSelect)
+	RegisterTyped(h, confirmedAction, h.handleConfirmedSelect)
+	RegisterTyped(h, confirmedReaffirmAction, h.handleConfirmedReaffirmSelect)
 	if h.verificationEnabled {
-		h.router.RegisterCallback("verification:", h.handleVerificationSelect)
-		h.router.RegisterCallback("verify:", h.handleVerifySelect)
+		RegisterTyped(h, verificationAction, h.handleVerificationSelect)
+		RegisterTyped(h, verifyAction, h.handleVerifySelect)
 	}
-	h.router.RegisterCallback("back:", h.handleBack)
-	h.router.RegisterCallback("accept_client:", h.handleAcceptClientSelect)
-	h.router.RegisterCallback("rate:", h.handleRateSelect)
+	RegisterTyped(h, backAction, h.handleBack)
+	RegisterTyped(h, acceptClientAction, h.handleAcceptClientSelect)
+	RegisterTyped(h, rateAction, h.handleRateSelect)
+
+	RegisterTyped(h, showMediaAction, h.handleShowMedia)
+	RegisterTyped(h, templateAction, h.handleTemplateSelect)
 
-	h.router.RegisterCallback("show_media:", h.handleShowMedia)
+	RegisterTyped(h, reviewApproveAction, h.handleReviewApprove)
+	RegisterTyped(h, reviewRejectAction, h.handleReviewReject)
+	RegisterTyped(h, reviewRejectReasonAction, h.handleReviewRejectReason)
+	RegisterTyped(h, reviewModerationBackAction, h.handleReviewModerationBack)
+	RegisterTyped(h, reviewAnonymousAction, h.handleReviewAnonymous)
+	RegisterTyped(h, reviewsPageAction, h.handleReviewsPage)
 
 	h.router.RegisterMessageHandler(h.handleMessage)
+	h.router.RegisterMyChatMemberHandler(h.handleMyChatMember)
 }
 
 func (h *Handler) Route(ctx context.Context, upd tgbotapi.Update) {
-	if !h.expertGuard(upd) {
-		logger.Log.Warnw("update blocked by expert guard")
-		return
-	}
-	if !h.supportGuard(upd) {
-		logger.Log.Warnw("update blocked by support guard")
-		return
-	}
-	if !h.stateGuard(ctx, upd) {
-		logger.Log.Warnw("update blocked by state guard")
-		return
-	}
-	if !h.startGuard(ctx, upd) {
-		logger.Log.Warnw("update blocked by start guard")
-		return
-	}
 	h.router.Route(ctx, upd)
 }
 
 func (h *Handler) deleteOrderMessage(ctx context.Context, orderID int) {
+	h.expertWaves.cancel(orderID)
+
 	sentOrders, err := h.orderMessageService.GetByOrder(ctx, orderID)
 	if err != nil {
 		logger.Log.Errorw("failed to get order messages",
@@ -204,17 +262,16 @@ func (h *Handler) deleteOrderMessage(ctx context.Context, orderID int) {
 	)
 
 	for _, sent := range sentOrders {
-		if _, err := h.bot.Request(tgbotapi.NewDeleteMessage(
-			sent.ChatID,
-			sent.MessageID,
-		)); err != nil {
-			wrapped := wrapTelegramErr("telegram.delete_message", err)
-			logger.Log.Errorw("failed to delete message",
-				"chat_id", sent.ChatID,
-				"message_id", sent.MessageID,
-				"err", wrapped,
-			)
-		}
+		h.post(
+			tgbotapi.NewDeleteMessage(
+				sent.ChatID,
+				sent.MessageID,
+			),
+			"telegram.delete_message",
+			nil,
+			"chat_id", sent.ChatID,
+			"message_id", sent.MessageID,
+		)
 	}
 
 	h.orderMessageService.MarkDeletedByOrder(ctx, orderID)
@@ -231,10 +288,10 @@ func (h *Handler) renderControlPanel(
 		action string
 	}, 0, 3)
 
-	tokenConfirmed, err := h.callbackTokenService.Create(
+	tokenConfirmed, err := confirmedAction.Create(
 		ctx,
-		"confirmed",
-		&ConfirmedAndDeclinedOrderSelectPayload{
+		h,
+		ConfirmedAndDeclinedOrderSelectPayload{
 			OrderID:  order.ID,
 			TopicID:  topicID,
 			ThreadID: threadID,
@@ -250,19 +307,19 @@ func (h *Handler) renderControlPanel(
 			action string
 		}{
 			token:  tokenConfirmed,
-			action: "confirmed",
+			action: confirmedAction.Name(),
 		})
 	}
 
 	btnAccept := tgbotapi.NewInlineKeyboardButtonData(
 		h.text.AcceptText,
-		"confirmed:"+tokenConfirmed,
+		confirmedAction.Data(tokenConfirmed),
 	)
 
-	tokenDeclined, err := h.callbackTokenService.Create(
+	tokenDeclined, err := declinedAction.Create(
 		ctx,
-		"declined",
-		&ConfirmedAndDeclinedOrderSelectPayload{
+		h,
+		ConfirmedAndDeclinedOrderSelectPayload{
 			OrderID:  order.ID,
 			TopicID:  topicID,
 			ThreadID: threadID,
@@ -278,13 +335,13 @@ func (h *Handler) renderControlPanel(
 			action string
 		}{
 			token:  tokenDeclined,
-			action: "declined",
+			action: declinedAction.Name(),
 		})
 	}
 
 	btnDecline := tgbotapi.NewInlineKeyboardButtonData(
 		h.text.DeclineText,
-		"declined:"+tokenDeclined,
+		declinedAction.Data(tokenDeclined),
 	)
 
 	acceptRow := []tgbotapi.InlineKeyboardButton{
@@ -301,10 +358,10 @@ func (h *Handler) renderControlPanel(
 	}
 
 	if h.verificationEnabled && !isVerified {
-		tokenVerification, err := h.callbackTokenService.Create(
+		tokenVerification, err := verificationAction.Create(
 			ctx,
-			"verification",
-			&VerificationSelectPayload{
+			h,
+			VerificationSelectPayload{
 				OrderID:    order.ID,
 				UserChatID: order.UserChatID,
 			},
@@ -319,13 +376,13 @@ func (h *Handler) renderControlPanel(
 				action string
 			}{
 				token:  tokenVerification,
-				action: "verification",
+				action: verificationAction.Name(),
 			})
 		}
 
 		btnVerification := tgbotapi.NewInlineKeyboardButtonData(
 			h.text.SendToVerificationText,
-			"verification:"+tokenVerification,
+			verificationAction.Data(tokenVerification),
 		)
 
 		keyboardRows = append(
@@ -349,7 +406,7 @@ func (h *Handler) renderControlPanel(
 	msg.MessageThreadID = threadID
 	msg.ReplyMarkup = markup
 
-	sent, err := h.bot.Send(msg)
+	sent, err := h.send(ctx, msg)
 	if err != nil {
 		wrapped := wrapTelegramErr("telegram.send_control_panel", err)
 		logger.Log.Errorw("failed to send control panel message",
@@ -399,10 +456,10 @@ func (h *Handler) renderEditControlPanel(
 		action string
 	}, 0, 3)
 
-	tokenConfirmed, err := h.callbackTokenService.Create(
+	tokenConfirmed, err := confirmedAction.Create(
 		ctx,
-		"confirmed",
-		&ConfirmedAndDeclinedOrderSelectPayload{
+		h,
+		ConfirmedAndDeclinedOrderSelectPayload{
 			OrderID:  order.ID,
 			TopicID:  topicID,
 			ThreadID: threadID,
@@ -418,19 +475,19 @@ func (h *Handler) renderEditControlPanel(
 			action string
 		}{
 			token:  tokenConfirmed,
-			action: "confirmed",
+			action: confirmedAction.Name(),
 		})
 	}
 
 	btnAccept := tgbotapi.NewInlineKeyboardButtonData(
 		h.text.AcceptText,
-		"confirmed:"+tokenConfirmed,
+		confirmedAction.Data(tokenConfirmed),
 	)
 
-	tokenDeclined, err := h.callbackTokenService.Create(
+	tokenDeclined, err := declinedAction.Create(
 		ctx,
-		"declined",
-		&ConfirmedAndDeclinedOrderSelectPayload{
+		h,
+		ConfirmedAndDeclinedOrderSelectPayload{
 			OrderID:  order.ID,
 			TopicID:  topicID,
 			ThreadID: threadID,
@@ -446,13 +503,13 @@ func (h *Handler) renderEditControlPanel(
 			action string
 		}{
 			token:  tokenDeclined,
-			action: "declined",
+			action: declinedAction.Name(),
 		})
 	}
 
 	btnDecline := tgbotapi.NewInlineKeyboardButtonData(
 		h.text.DeclineText,
-		"declined:"+tokenDeclined,
+		declinedAction.Data(tokenDeclined),
 	)
 
 	keyboardRows := [][]tgbotapi.InlineKeyboardButton{
@@ -461,10 +518,10 @@ func (h *Handler) renderEditControlPanel(
 	}
 
 	if h.verificationEnabled && !isVerified {
-		tokenVerification, err := h.callbackTokenService.Create(
+		tokenVerification, err := verificationAction.Create(
 			ctx,
-			"verification",
-			&VerificationSelectPayload{
+			h,
+			VerificationSelectPayload{
 				OrderID:    order.ID,
 				UserChatID: order.UserChatID,
 			},
@@ -479,13 +536,13 @@ func (h *Handler) renderEditControlPanel(
 				action string
 			}{
 				token:  tokenVerification,
-				action: "verification",
+				action: verificationAction.Name(),
 			})
 		}
 
 		btnVerification := tgbotapi.NewInlineKeyboardButtonData(
 			h.text.SendToVerificationText,
-			"verification:"+tokenVerification,
+			verificationAction.Data(tokenVerification),
 		)
 
 		keyboardRows = append(keyboardRows, []tgbotapi.InlineKeyboardButton{btnVerification})
@@ -504,13 +561,7 @@ func (h *Handler) renderEditControlPanel(
 	)
 	editMessage.ReplyMarkup = &markup
 
-	if _, err := h.bot.Send(editMessage); err != nil {
-		wrapped := wrapTelegramErr("telegram.edit_control_panel", err)
-		logger.Log.Errorw("failed to edit control panel message",
-			"topic_id", topicID,
-			"message_id", messageID,
-			"err", wrapped,
-		)
+	dropTokens := func(error) {
 		for _, item := range createdTokens {
 			if err := h.callbackTokenService.Delete(
 				ctx,
@@ -525,33 +576,23 @@ func (h *Handler) renderEditControlPanel(
 			}
 		}
 	}
+
+	h.post(editMessage, "telegram.edit_control_panel", dropTokens,
+		"topic_id", topicID,
+		"message_id", messageID,
+	)
 }
 
 func (h *Handler) expertGuard(
-	upd tgbotapi.Update,
+	ctx context.Context,
+	uc *UpdateContext,
 ) bool {
-
-	chatID, ok := extractChatID(upd)
-	if !ok {
-		return true
-	}
-
-	experts, err := h.expertService.GetAllExperts()
-	if err != nil {
+	if uc.Role != RoleExpert {
 		return true
 	}
 
-	isExpert := false
-	for _, e := range experts {
-		if chatID == e.TopicID {
-			isExpert = true
-			break
-		}
-	}
-
-	if !isExpert {
-		return true
-	}
+	upd := uc.Update
+	chatID := uc.ChatID
 
 	if upd.CallbackQuery != nil {
 		return true
@@ -565,9 +606,11 @@ func (h *Handler) expertGuard(
 		"chat_id", chatID,
 	)
 
-	if upd.Message != nil && upd.Message.IsCommand() &&
-		upd.Message.Command() == "start" {
-		return true
+	if upd.Message != nil && upd.Message.IsCommand() {
+		switch upd.Message.Command() {
+		case "start", "tpl":
+			return true
+		}
 	}
 
 	logger.Log.Warnw("expert action blocked",
@@ -578,23 +621,19 @@ func (h *Handler) expertGuard(
 }
 
 func (h *Handler) supportGuard(
-	upd tgbotapi.Update,
+	ctx context.Context,
+	uc *UpdateContext,
 ) bool {
-
-	chatID, ok := extractChatID(upd)
-	if !ok {
+	if uc.Role != RoleSupport {
 		return true
 	}
 
-	support := h.supportService.GetSupport()
-
-	if chatID != support.ChatID {
-		return true
-	}
+	upd := uc.Update
+	chatID := uc.ChatID
 
 	if upd.Message != nil && upd.Message.IsCommand() {
 		switch upd.Message.Command() {
-		case "start", "search":
+		case "start", "search", "outbox", "moderation":
 			return true
 		default:
 			logger.Log.Warnw("support action blocked",
@@ -616,8 +655,10 @@ func (h *Handler) supportGuard(
 
 func (h *Handler) stateGuard(
 	ctx context.Context,
-	upd tgbotapi.Update,
+	uc *UpdateContext,
 ) bool {
+	upd := uc.Update
+
 	if upd.Message != nil {
 		if upd.Message.Chat.IsSuperGroup() {
 			return true
@@ -630,12 +671,12 @@ func (h *Handler) stateGuard(
 		}
 	}
 
-	chatID, ok := extractChatID(upd)
-	if !ok {
+	if !uc.HasChat {
 		return true
 	}
+	chatID := uc.ChatID
 
-	state, err := h.stateService.GetStateByChatID(ctx, chatID)
+	state, err := uc.State(ctx)
 	if err != nil {
 		return true
 	}
@@ -660,17 +701,29 @@ func (h *Handler) stateGuard(
 		return true
 	}
 
+	if upd.Message != nil && upd.Message.IsCommand() &&
+		upd.Message.Command() == "start" &&
+		h.returningAfterBlock(ctx, uc, state.OrderID) {
+		h.sendResumePrompt(ctx, chatID, state.OrderID)
+
+		logger.Log.Infow("start command during communication, resume prompt sent",
+			"user_chat_id", chatID,
+			"order_id", state.OrderID,
+		)
+
+		return false
+	}
+
 	if upd.Message != nil && upd.Message.IsCommand() {
-		if _, err := h.bot.Send(tgbotapi.NewMessage(
-			chatID,
-			h.text.CommunicationBlockedCommandText,
-		)); err != nil {
-			wrapped := wrapTelegramErr("telegram.send_communication_blocked", err)
-			logger.Log.Errorw("failed to send communication blocked message",
-				"chat_id", chatID,
-				"err", wrapped,
-			)
-		}
+		h.post(
+			tgbotapi.NewMessage(
+				chatID,
+				h.text.CommunicationBlockedCommandText,
+			),
+			"telegram.send_communication_blocked",
+			nil,
+			"chat_id", chatID,
+		)
 
 		logger.Log.Warnw("command blocked during communication",
 			"user_chat_id", chatID,
@@ -685,11 +738,11 @@ func (h *Handler) stateGuard(
 	}
 
 	if upd.CallbackQuery != nil {
-		if strings.HasPrefix(upd.CallbackQuery.Data, "accept_client:") {
+		if strings.HasPrefix(upd.CallbackQuery.Data, acceptClientAction.Data("")) {
 			return true
 		}
 
-		if strings.HasPrefix(upd.CallbackQuery.Data, "verify:") {
+		if strings.HasPrefix(upd.CallbackQuery.Data, verifyAction.Data("")) {
 			return true
 		}
 
@@ -711,35 +764,14 @@ func (h *Handler) stateGuard(
 
 func (h *Handler) startGuard(
 	ctx context.Context,
-	upd tgbotapi.Update,
+	uc *UpdateContext,
 ) bool {
-	chatID, ok := extractChatID(upd)
-	if !ok {
-		return true
-	}
-
-	experts, err := h.expertService.GetAllExperts()
-	if err != nil {
-		return true
-	}
-
-	isExpert := false
-	for _, e := range experts {
-		if chatID == e.TopicID {
-			isExpert = true
-			break
-		}
-	}
-
-	if isExpert {
+	if uc.Role != RoleUser {
 		return true
 	}
 
-	support := h.supportService.GetSupport()
-
-	if chatID == support.ChatID {
-		return true
-	}
+	upd := uc.Update
+	chatID := uc.ChatID
 
 	if upd.Message != nil && upd.Message.IsCommand() &&
 		upd.Message.Command() == "start" {
@@ -750,20 +782,16 @@ func (h *Handler) startGuard(
 		return true
 	}
 
-	accepted, _ := h.userPolicyAcceptancesService.IsAccepted(ctx, chatID)
+	accepted, _ := uc.PolicyAccepted(ctx)
 	if !accepted {
 		message := tgbotapi.NewMessage(
 			chatID,
 			h.text.NeedAcceptRulesText,
 		)
 		message.ParseMode = "Markdown"
-		if _, err := h.bot.Send(message); err != nil {
-			wrapped := wrapTelegramErr("telegram.send_need_accept_rules", err)
-			logger.Log.Errorw("failed to send accept rules message",
-				"chat_id", chatID,
-				"err", wrapped,
-			)
-		}
+		h.post(message, "telegram.send_need_accept_rules", nil,
+			"chat_id", chatID,
+		)
 
 		logger.Log.Warnw("command is blocked, the user did not accept rules",
 			"user_chat_id", chatID,
@@ -787,6 +815,11 @@ func shouldAutoPublishReview(upd tgbotapi.Update) bool {
 		}
 	}
 
+	if upd.CallbackQuery != nil &&
+		strings.HasPrefix(upd.CallbackQuery.Data, reviewAnonymousAction.Data("")) {
+		return false
+	}
+
 	return true
 }
 
@@ -811,6 +844,8 @@ func (h *Handler) publishPendingReview(
 	)
 
 	h.stateService.SetStateIdle(ctx, *state.UserChatID)
+
+	h.postReviewToChannel(ctx, *state.ReviewID)
 }
 
 func extractChatID(upd tgbotapi.Update) (int64, bool) {
@@ -872,7 +907,7 @@ func (h *Handler) answerCallback(
 	cb *tgbotapi.CallbackQuery,
 	text string,
 ) {
-	if _, err := h.bot.Request(tgbotapi.NewCallback(cb.ID, text)); err != nil {
+	if _, err := h.request(tgbotapi.NewCallback(cb.ID, text)); err != nil {
 		wrapped := wrapTelegramErr("telegram.answer_callback", err)
 		logger.Log.Errorw("failed to answer callback",
 			"callback_id", cb.ID,
@@ -881,6 +916,19 @@ func (h *Handler) answerCallback(
 	}
 }
 
+func (h *Handler) answerCallbackAlert(
+	cb *tgbotapi.CallbackQuery,
+	text string,
+) {
+	if _, err := h.request(tgbotapi.NewCallbackWithAlert(cb.ID, text)); err != nil {
+		wrapped := wrapTelegramErr("telegram.answer_callback_alert", err)
+		logger.Log.Errorw("failed to answer callback with alert",
+			"callback_id", cb.ID,
+			"err", wrapped,
+		)
+	}
+}
+
 func (h *Handler) supportOnly(handler HandlerFunc) HandlerFunc {
 	return func(ctx context.Context, msg *tgbotapi.Message) {
 		support := h.supportService.GetSupport()
@@ -6,7 +6,9 @@ import (
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/m4xvel/monetych_bot/internal/domain"
+	"github.com/m4xvel/monetych_bot/internal/fraud"
 	"github.com/m4xvel/monetych_bot/internal/logger"
+	"github.com/m4xvel/monetych_bot/internal/redact"
 )
 
 func (h *Handler) handleMessage(
@@ -58,6 +60,34 @@ func (h *Handler) handleUserMessage(
 
 		media, msgType := extractMedia(msg)
 
+		fraudHits := h.fraudHitService.Inspect(
+			ctx,
+			*state.OrderID,
+			domain.SenderUser,
+			chatID,
+			msg.MessageID,
+			text,
+		)
+
+		redacted := h.redactText(text)
+		if redacted.Redacted {
+			logger.Log.Infow("sensitive data redacted in user message",
+				"chat_id", chatID,
+				"order_id", state.OrderID,
+				"kinds", redacted.Kinds,
+			)
+		}
+
+		var relay *domain.OutboxJob
+		if fraud.HasAction(fraudHits, fraud.ActionBlock) {
+			logger.Log.Warnw("user message relay blocked by fraud rules",
+				"chat_id", chatID,
+				"order_id", state.OrderID,
+			)
+		} else {
+			relay = userRelayJob(state, msg, redacted)
+		}
+
 		if err := h.orderChatMessageService.SaveUserMessage(
 			ctx,
 			*state.OrderID,
@@ -67,6 +97,8 @@ func (h *Handler) handleUserMessage(
 			msgType,
 			text,
 			media,
+			redacted.Redacted,
+			relay,
 		); err != nil {
 			logger.Log.Errorw("failed to save user message",
 				"err", err,
@@ -78,7 +110,15 @@ func (h *Handler) handleUserMessage(
 			"order_id", state.OrderID,
 		)
 
-		h.forwardToExpert(state, msg)
+		h.wakeOutbox()
+
+		h.applyFraudActions(
+			ctx,
+			*state.OrderID,
+			domain.SenderUser,
+			msg,
+			fraudHits,
+		)
 
 	case domain.StateStart:
 		logger.Log.Infow("user message in start state redirected to catalog",
@@ -120,83 +160,63 @@ func (h *Handler) handleUserMessage(
 			return
 		}
 
-		if err := h.reviewService.Publish(ctx, *state.ReviewID); err != nil {
-			logger.Log.Errorw("failed to publish review",
+		if err := h.stateService.SetStateIdle(ctx, chatID); err != nil {
+			logger.Log.Errorw("failed to set idle state after review",
 				"chat_id", chatID,
-				"review_id", *state.ReviewID,
 				"err", err,
 			)
-			return
 		}
 
-		logger.Log.Infow("review published",
+		h.post(
+			tgbotapi.NewMessage(chatID, h.text.ReviewSentToModerationText),
+			"telegram.send_thanks_review",
+			nil,
 			"chat_id", chatID,
 			"review_id", *state.ReviewID,
 		)
 
-		if err := h.stateService.SetStateIdle(ctx, chatID); err != nil {
-			logger.Log.Errorw("failed to set idle state after review",
-				"chat_id", chatID,
-				"err", err,
-			)
-		}
-
-		if _, err := h.bot.Send(tgbotapi.NewMessage(chatID, h.text.ThanksForReviewText)); err != nil {
-			wrapped := wrapTelegramErr("telegram.send_thanks_review", err)
-			logger.Log.Errorw("failed to send thanks for review",
-				"chat_id", chatID,
-				"review_id", *state.ReviewID,
-				"err", wrapped,
-			)
-		}
+		h.submitReviewForModeration(ctx, *state.ReviewID)
 	}
 }
 
-func (h *Handler) forwardToExpert(
+func userRelayJob(
 	state *domain.UserState,
 	msg *tgbotapi.Message,
-) {
+	redacted redact.Result,
+) *domain.OutboxJob {
 	if state.ExpertTopicID == nil || state.OrderThreadID == nil {
-		return
+		return nil
 	}
 
-	params := tgbotapi.Params{
+	method := "copyMessage"
+	params := map[string]string{
 		"chat_id":           int64PtrToStr(state.ExpertTopicID),
 		"from_chat_id":      fmt.Sprint(msg.Chat.ID),
 		"message_id":        fmt.Sprint(msg.MessageID),
 		"message_thread_id": int64PtrToStr(state.OrderThreadID),
 	}
 
-	if h.copyMessageQueue == nil {
-		if err := retryOnRateLimit(
-			"telegram.copy_user_message",
-			func() error {
-				_, err := h.bot.MakeRequest("copyMessage", params)
-				return err
-			},
-			"chat_id", state.ExpertTopicID,
-			"order_id", state.OrderID,
-		); err != nil {
-			wrapped := wrapTelegramErr("telegram.copy_user_message", err)
-			logger.Log.Errorw("failed to forward user message to expert",
-				"order_id", state.OrderID,
-				"err", wrapped,
-			)
+	if redacted.Redacted {
+		switch {
+		case msg.Text != "":
+			method = "sendMessage"
+			params = map[string]string{
+				"chat_id":           int64PtrToStr(state.ExpertTopicID),
+				"message_thread_id": int64PtrToStr(state.OrderThreadID),
+				"text":              redacted.Text,
+			}
+		case msg.Caption != "":
+			params["caption"] = redacted.Text
 		}
-		return
 	}
 
-	h.copyMessageQueue.enqueue(sendJob{
-		op: "telegram.copy_user_message",
-		fn: func() error {
-			_, err := h.bot.MakeRequest("copyMessage", params)
-			return err
-		},
-		fields: []any{
-			"chat_id", state.ExpertTopicID,
-			"order_id", state.OrderID,
-		},
-	})
+	return &domain.OutboxJob{
+		IdempotencyKey: relayIdempotencyKey(msg),
+		OrderID:        state.OrderID,
+		ChatID:         *state.ExpertTopicID,
+		Method:         method,
+		Params:         params,
+	}
 }
 
 func (h *Handler) handleExpertMessage(
@@ -234,6 +254,34 @@ func (h *Handler) handleExpertMessage(
 
 	media, msgType := extractMedia(msg)
 
+	fraudHits := h.fraudHitService.Inspect(
+		ctx,
+		*state.OrderID,
+		domain.SenderExpert,
+		msg.Chat.ID,
+		msg.MessageID,
+		text,
+	)
+
+	var relay *domain.OutboxJob
+	if fraud.HasAction(fraudHits, fraud.ActionBlock) {
+		logger.Log.Warnw("expert message relay blocked by fraud rules",
+			"order_id", state.OrderID,
+		)
+	} else {
+		relay = &domain.OutboxJob{
+			IdempotencyKey: relayIdempotencyKey(msg),
+			OrderID:        state.OrderID,
+			ChatID:         *state.UserChatID,
+			Method:         "copyMessage",
+			Params: map[string]string{
+				"chat_id":      int64PtrToStr(state.UserChatID),
+				"from_chat_id": fmt.Sprint(msg.Chat.ID),
+				"message_id":   fmt.Sprint(msg.MessageID),
+			},
+		}
+	}
+
 	if err := h.orderChatMessageService.SaveExpertMessage(
 		ctx,
 		*state.OrderID,
@@ -243,6 +291,7 @@ func (h *Handler) handleExpertMessage(
 		msgType,
 		text,
 		media,
+		relay,
 	); err != nil {
 		logger.Log.Errorw("failed to save expert message",
 			"err", err,
@@ -254,49 +303,28 @@ func (h *Handler) handleExpertMessage(
 		"order_id", state.OrderID,
 	)
 
-	params := tgbotapi.Params{
-		"chat_id":      int64PtrToStr(state.UserChatID),
-		"from_chat_id": fmt.Sprint(msg.Chat.ID),
-		"message_id":   fmt.Sprint(msg.MessageID),
-	}
+	h.wakeOutbox()
 
-	if h.copyMessageQueue == nil {
-		if err := retryOnRateLimit(
-			"telegram.copy_expert_message",
-			func() error {
-				_, err := h.bot.MakeRequest("copyMessage", params)
-				return err
-			},
-			"chat_id", state.UserChatID,
-			"order_id", state.OrderID,
-		); err != nil {
-			wrapped := wrapTelegramErr("telegram.copy_expert_message", err)
-			logger.Log.Errorw("failed to forward expert message to user",
-				"order_id", state.OrderID,
-				"user_chat_id", state.UserChatID,
-				"err", wrapped,
-			)
-			return
-		}
-	} else {
-		h.copyMessageQueue.enqueue(sendJob{
-			op: "telegram.copy_expert_message",
-			fn: func() error {
-				_, err := h.bot.MakeRequest("copyMessage", params)
-				return err
-			},
-			fields: []any{
-				"chat_id", state.UserChatID,
-				"order_id", state.OrderID,
-			},
-		})
-	}
+	h.applyFraudActions(
+		ctx,
+		*state.OrderID,
+		domain.SenderExpert,
+		msg,
+		fraudHits,
+	)
 
 	logger.Log.Infow("expert message queued to user",
 		"order_id", state.OrderID,
 	)
 }
 
+func (h *Handler) redactText(text *string) redact.Result {
+	if text == nil {
+		return redact.Result{}
+	}
+	return h.redactor.Apply(*text)
+}
+
 func extractText(msg *tgbotapi.Message) *string {
 	switch {
 	case msg.Text != "":
@@ -0,0 +1,78 @@
+package telegram
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/m4xvel/monetych_bot/internal/metrics"
+)
+
+var (
+	updatesTotal = metrics.NewCounterVec(
+		"bot_updates_total",
+		"Telegram updates received, by update type.",
+		"type",
+	)
+	handlerDuration = metrics.NewHistogramVec(
+		"bot_handler_duration_seconds",
+		"Time spent handling an update, by route.",
+		metrics.DefaultBuckets,
+		"route",
+	)
+	callbackTokenFailures = metrics.NewCounterVec(
+		"bot_callback_token_failures_total",
+		"Callback tokens that could not be consumed, by action and reason.",
+		"action", "reason",
+	)
+	telegramRateLimited = metrics.NewCounterVec(
+		"bot_telegram_rate_limited_total",
+		"Telegram requests retried after a 429 response, by operation.",
+		"op",
+	)
+)
+
+// MetricsMiddleware counts updates and times the rest of the chain, guards
+// included, so blocked updates show up as fast routes rather than vanishing.
+func (r *Router) MetricsMiddleware(next UpdateHandler) UpdateHandler {
+	return func(ctx context.Context, uc *UpdateContext) {
+		start := time.Now()
+		updateType, route := r.classifyUpdate(uc)
+		updatesTotal.Inc(updateType)
+
+		next(ctx, uc)
+
+		handlerDuration.ObserveSince(start, route)
+	}
+}
+
+// classifyUpdate returns the update type and a route: the command name,
+// the callback action, or the update type itself. Commands and actions the
+// router doesn't know are collapsed so users can't inflate label cardinality.
+func (r *Router) classifyUpdate(uc *UpdateContext) (string, string) {
+	upd := uc.Update
+
+	switch {
+	case upd.Message != nil:
+		if upd.Message.IsCommand() {
+			cmd := upd.Message.Command()
+			if _, ok := r.commandHandlers[cmd]; !ok {
+				cmd = "unknown"
+			}
+			return "message", "command:" + cmd
+		}
+		return "message", "message"
+	case upd.EditedMessage != nil:
+		return "edited_message", "edited_message"
+	case upd.CallbackQuery != nil:
+		action, _, _ := strings.Cut(upd.CallbackQuery.Data, ":")
+		if _, ok := r.callbackHandlers[action+":"]; !ok {
+			action = "unknown"
+		}
+		return "callback_query", "callback:" + action
+	case upd.MyChatMember != nil:
+		return "my_chat_member", "my_chat_member"
+	default:
+		return "other", "other"
+	}
+}
@@ -0,0 +1,151 @@
+package telegram
+
+import (
+	"context"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/m4xvel/monetych_bot/internal/domain"
+	"github.com/m4xvel/monetych_bot/internal/logger"
+)
+
+type ChatRole string
+
+const (
+	RoleUnknown ChatRole = ""
+	RoleUser    ChatRole = "user"
+	RoleExpert  ChatRole = "expert"
+	RoleSupport ChatRole = "support"
+)
+
+// UpdateContext carries what middlewares learn about an update so later
+// steps don't resolve it again. State, user and policy acceptance are loaded
+// on first use.
+type UpdateContext struct {
+	Update  tgbotapi.Update
+	ChatID  int64
+	HasChat bool
+	Role    ChatRole
+
+	loadState    func(ctx context.Context, chatID int64) (*domain.UserState, error)
+	loadUser     func(ctx context.Context, chatID int64) (*domain.User, error)
+	loadAccepted func(ctx context.Context, chatID int64) (bool, error)
+
+	state       *domain.UserState
+	stateErr    error
+	stateLoaded bool
+
+	user       *domain.User
+	userErr    error
+	userLoaded bool
+
+	accepted       bool
+	acceptedErr    error
+	acceptedLoaded bool
+}
+
+func NewUpdateContext(upd tgbotapi.Update) *UpdateContext {
+	chatID, ok := extractChatID(upd)
+	return &UpdateContext{
+		Update:  upd,
+		ChatID:  chatID,
+		HasChat: ok,
+	}
+}
+
+func (uc *UpdateContext) State(ctx context.Context) (*domain.UserState, error) {
+	if !uc.stateLoaded && uc.loadState != nil && uc.HasChat {
+		uc.state, uc.stateErr = uc.loadState(ctx, uc.ChatID)
+		uc.stateLoaded = true
+	}
+	return uc.state, uc.stateErr
+}
+
+func (uc *UpdateContext) User(ctx context.Context) (*domain.User, error) {
+	if !uc.userLoaded && uc.loadUser != nil && uc.HasChat {
+		uc.user, uc.userErr = uc.loadUser(ctx, uc.ChatID)
+		uc.userLoaded = true
+	}
+	return uc.user, uc.userErr
+}
+
+func (uc *UpdateContext) PolicyAccepted(ctx context.Context) (bool, error) {
+	if !uc.acceptedLoaded && uc.loadAccepted != nil && uc.HasChat {
+		uc.accepted, uc.acceptedErr = uc.loadAccepted(ctx, uc.ChatID)
+		uc.acceptedLoaded = true
+	}
+	return uc.accepted, uc.acceptedErr
+}
+
+type UpdateHandler func(ctx context.Context, uc *UpdateContext)
+
+type Middleware func(next UpdateHandler) UpdateHandler
+
+// GuardFunc reports whether the update may continue down the chain.
+type GuardFunc func(ctx context.Context, uc *UpdateContext) bool
+
+func Guard(name string, guard GuardFunc) Middleware {
+	return func(next UpdateHandler) UpdateHandler {
+		return func(ctx context.Context, uc *UpdateContext) {
+			if !guard(ctx, uc) {
+				logger.Log.Warnw("update blocked by "+name,
+					"chat_id", uc.ChatID,
+					"role", uc.Role,
+				)
+				return
+			}
+			next(ctx, uc)
+		}
+	}
+}
+
+func RecoverMiddleware(next UpdateHandler) UpdateHandler {
+	return func(ctx context.Context, uc *UpdateContext) {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Log.Errorw("panic in update handler",
+					"update_id", uc.Update.UpdateID,
+					"chat_id", uc.ChatID,
+					"panic", r,
+				)
+			}
+		}()
+		next(ctx, uc)
+	}
+}
+
+func LoggingMiddleware(next UpdateHandler) UpdateHandler {
+	return func(ctx context.Context, uc *UpdateContext) {
+		start := time.Now()
+		next(ctx, uc)
+		logger.Log.Debugw("update processed",
+			"update_id", uc.Update.UpdateID,
+			"chat_id", uc.ChatID,
+			"role", uc.Role,
+			"duration", time.Since(start),
+		)
+	}
+}
+
+func (h *Handler) resolveMiddleware(next UpdateHandler) UpdateHandler {
+	return func(ctx context.Context, uc *UpdateContext) {
+		uc.Role = h.resolveRole(uc)
+		uc.loadState = h.stateService.GetStateByChatID
+		uc.loadUser = h.userService.GetByChatID
+		uc.loadAccepted = h.userPolicyAcceptancesService.IsAccepted
+		next(ctx, uc)
+	}
+}
+
+func (h *Handler) resolveRole(uc *UpdateContext) ChatRole {
+	if !uc.HasChat {
+		return RoleUnknown
+	}
+	if uc.ChatID == h.supportService.GetSupport().ChatID {
+		return RoleSupport
+	}
+	if h.isExpertChat(uc.ChatID) {
+		return RoleExpert
+	}
+	return RoleUser
+}
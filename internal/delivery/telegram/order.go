@@ -2,15 +2,16 @@ package telegram
 
 import (
 	"context"
-	"strings"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/m4xvel/monetych_bot/internal/domain"
 	"github.com/m4xvel/monetych_bot/internal/logger"
 )
 
 func (h *Handler) handleOrderSelect(
 	ctx context.Context,
 	cb *tgbotapi.CallbackQuery,
+	payload OrderSelectPayload,
 ) {
 	chatID := cb.Message.Chat.ID
 	messageID := cb.Message.MessageID
@@ -20,44 +21,6 @@ func (h *Handler) handleOrderSelect(
 		"chat_id", chatID,
 	)
 
-	parts := strings.Split(cb.Data, ":")
-	if len(parts) != 2 {
-		logger.Log.Warnw("invalid order callback data",
-			"chat_id", chatID,
-			"data", cb.Data,
-		)
-		return
-	}
-
-	tokenCallback := parts[1]
-
-	var payload OrderSelectPayload
-
-	if err := h.callbackTokenService.Consume(
-		ctx,
-		tokenCallback,
-		"order",
-		&payload,
-	); err != nil {
-		if isInvalidToken(err) {
-			logger.Log.Warnw("invalid order callback token",
-				"chat_id", chatID,
-				"data", cb.Data,
-				"err", err,
-			)
-			return
-		}
-		logger.Log.Errorw("failed to consume order callback token",
-			"chat_id", chatID,
-			"err", err,
-		)
-		return
-	}
-
-	if payload.ChatID != cb.From.ID {
-		return
-	}
-
 	gameID := payload.GameID
 	gameTypeID := payload.TypeID
 
@@ -104,6 +67,7 @@ func (h *Handler) handleOrderSelect(
 			"game_type_id", gameTypeID,
 			"err", err,
 		)
+		releaseCallback(ctx)
 		return
 	}
 
@@ -115,17 +79,16 @@ func (h *Handler) handleOrderSelect(
 			"game_type_id", gameTypeID,
 		)
 
-		if _, err := h.bot.Send(tgbotapi.NewEditMessageText(
-			chatID,
-			messageID,
-			h.text.AlreadyActiveOrder,
-		)); err != nil {
-			wrapped := wrapTelegramErr("telegram.edit_already_active", err)
-			logger.Log.Errorw("failed to edit already active order message",
-				"chat_id", chatID,
-				"err", wrapped,
-			)
-		}
+		h.post(
+			tgbotapi.NewEditMessageText(
+				chatID,
+				messageID,
+				h.text.AlreadyActiveOrder,
+			),
+			"telegram.edit_already_active",
+			nil,
+			"chat_id", chatID,
+		)
 		return
 	}
 
@@ -135,10 +98,10 @@ func (h *Handler) handleOrderSelect(
 		"chat_id", chatID,
 	)
 
-	token, err := h.callbackTokenService.Create(
+	token, err := cancelAction.Create(
 		ctx,
-		"cancel",
-		&CancelOrderSelectPayload{
+		h,
+		CancelOrderSelectPayload{
 			ChatID:  chatID,
 			OrderID: id,
 		},
@@ -152,7 +115,7 @@ func (h *Handler) handleOrderSelect(
 
 	btn := tgbotapi.NewInlineKeyboardButtonData(
 		h.text.DeclineText,
-		"cancel:"+token,
+		cancelAction.Data(token),
 	)
 
 	markup := tgbotapi.NewInlineKeyboardMarkup(
@@ -166,7 +129,7 @@ func (h *Handler) handleOrderSelect(
 	)
 	edit.ReplyMarkup = &markup
 
-	send, err := h.bot.Send(edit)
+	send, err := h.send(ctx, edit)
 	if err != nil {
 		wrapped := wrapTelegramErr("telegram.edit_waiting_assessor", err)
 		logger.Log.Errorw("failed to edit waiting assessor message",
@@ -174,7 +137,7 @@ func (h *Handler) handleOrderSelect(
 			"order_id", id,
 			"err", wrapped,
 		)
-		if err := h.callbackTokenService.Delete(ctx, token, "cancel"); err != nil {
+		if err := cancelAction.Delete(ctx, h, token); err != nil {
 			logger.Log.Errorw("failed to cleanup cancel callback token",
 				"chat_id", chatID,
 				"order_id", id,
@@ -187,6 +150,8 @@ func (h *Handler) handleOrderSelect(
 	h.notifyExpertsAboutOrder(ctx, id, send.MessageID, chatID, g.Name, t.Name)
 }
 
+// notifyExpertsAboutOrder sends the order to the best ranked experts first.
+// The rest get it once the head start is over, unless it was taken by then.
 func (h *Handler) notifyExpertsAboutOrder(
 	ctx context.Context,
 	orderID, messageID int,
@@ -206,72 +171,121 @@ func (h *Handler) notifyExpertsAboutOrder(
 		return
 	}
 
-	for _, e := range experts {
-		token, err := h.callbackTokenService.Create(
-			ctx,
-			"accept",
-			&AcceptOrderSelectPayload{
-				ChatID:        chatID,
-				OrderID:       orderID,
-				UserMessageID: messageID,
-				ExpertID:      e.ID,
-			},
+	activeOrders, err := h.orderService.CountActiveByExpert(ctx)
+	if err != nil {
+		logger.Log.Warnw("failed to count active orders, ranking experts by rating only",
+			"order_id", orderID,
+			"err", err,
 		)
-		if err != nil {
-			logger.Log.Errorw("failed to create accept order callback token",
-				"chat_id", chatID,
-				"err", err,
+	}
+
+	ranked := h.expertRatingService.Rank(experts, activeOrders)
+	first, rest := ranked, []domain.Expert(nil)
+	if h.expertHeadStart > 0 && h.expertFirstWave > 0 &&
+		len(ranked) > h.expertFirstWave {
+		first, rest = ranked[:h.expertFirstWave], ranked[h.expertFirstWave:]
+	}
+
+	notify := func(ctx context.Context, wave []domain.Expert) {
+		for _, e := range wave {
+			h.notifyExpertAboutOrder(
+				ctx, e, orderID, messageID, chatID, gameName, gameTypeName,
 			)
 		}
-
-		acceptButton := tgbotapi.NewInlineKeyboardButtonData(
-			h.text.AcceptOrderButtonText,
-			"accept:"+token,
+		logger.Log.Infow("experts notified",
+			"order_id", orderID,
+			"experts_count", len(wave),
 		)
+	}
 
-		message := tgbotapi.NewMessage(
-			e.TopicID,
-			h.textDynamic.NewOrder(orderID, gameName, gameTypeName),
-		)
+	notify(ctx, first)
 
-		message.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
-			tgbotapi.NewInlineKeyboardRow(acceptButton),
-		)
+	if len(rest) == 0 {
+		return
+	}
 
-		send, err := h.bot.Send(message)
+	h.expertWaves.schedule(orderID, h.expertHeadStart, func(ctx context.Context) {
+		order, err := h.orderService.GetOrderByID(ctx, orderID)
 		if err != nil {
-			wrapped := wrapTelegramErr("telegram.notify_expert", err)
-			logger.Log.Errorw("failed to notify expert",
+			logger.Log.Errorw("failed to get order for delayed expert notification",
 				"order_id", orderID,
-				"expert_id", e.ID,
-				"err", wrapped,
+				"err", err,
 			)
-			if err := h.callbackTokenService.Delete(ctx, token, "accept"); err != nil {
-				logger.Log.Errorw("failed to cleanup accept callback token",
-					"order_id", orderID,
-					"expert_id", e.ID,
-					"err", err,
-				)
-			}
-			continue
+			return
 		}
+		if order == nil || order.Status != domain.OrderNew {
+			return
+		}
+		notify(ctx, rest)
+	})
+}
 
-		if err := h.orderMessageService.Save(
-			ctx,
-			orderID,
-			send.Chat.ID,
-			send.MessageID,
-		); err != nil {
-			logger.Log.Errorw("failed to save order message",
+func (h *Handler) notifyExpertAboutOrder(
+	ctx context.Context,
+	e domain.Expert,
+	orderID, messageID int,
+	chatID int64,
+	gameName, gameTypeName string,
+) {
+	token, err := acceptAction.Create(
+		ctx,
+		h,
+		AcceptOrderSelectPayload{
+			ChatID:        chatID,
+			OrderID:       orderID,
+			UserMessageID: messageID,
+			ExpertID:      e.ID,
+		},
+	)
+	if err != nil {
+		logger.Log.Errorw("failed to create accept order callback token",
+			"chat_id", chatID,
+			"err", err,
+		)
+	}
+
+	acceptButton := tgbotapi.NewInlineKeyboardButtonData(
+		h.text.AcceptOrderButtonText,
+		acceptAction.Data(token),
+	)
+
+	message := tgbotapi.NewMessage(
+		e.TopicID,
+		h.textDynamic.NewOrder(orderID, gameName, gameTypeName),
+	)
+
+	message.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(acceptButton),
+	)
+
+	send, err := h.send(ctx, message)
+	if err != nil {
+		wrapped := wrapTelegramErr("telegram.notify_expert", err)
+		logger.Log.Errorw("failed to notify expert",
+			"order_id", orderID,
+			"expert_id", e.ID,
+			"err", wrapped,
+		)
+		if err := acceptAction.Delete(ctx, h, token); err != nil {
+			logger.Log.Errorw("failed to cleanup accept callback token",
 				"order_id", orderID,
 				"expert_id", e.ID,
 				"err", err,
 			)
 		}
+		return
+	}
 
-		logger.Log.Infow("experts notified",
+	if err := h.orderMessageService.Save(
+		ctx,
+		orderID,
+		send.Chat.ID,
+		send.MessageID,
+	); err != nil {
+		logger.Log.Errorw("failed to save order message",
 			"order_id", orderID,
-			"experts_count", len(experts),
+			"expert_id", e.ID,
+			"err", err,
 		)
 	}
 }
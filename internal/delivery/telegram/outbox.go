@@ -0,0 +1,274 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/m4xvel/monetych_bot/internal/apperr"
+	"github.com/m4xvel/monetych_bot/internal/domain"
+	"github.com/m4xvel/monetych_bot/internal/logger"
+)
+
+const (
+	outboxPollInterval = time.Second
+	outboxBatchSize    = 100
+	outboxLease        = 10 * time.Minute
+	outboxMaxAttempts  = 10
+	outboxBaseBackoff  = 5 * time.Second
+	outboxMaxBackoff   = 30 * time.Minute
+	outboxStuckAfter   = 5 * time.Minute
+	outboxStuckLimit   = 20
+	outboxOpTimeout    = 10 * time.Second
+	outboxCleanupEvery = time.Hour
+	outboxSentRetain   = 7 * 24 * time.Hour
+)
+
+type outboxDispatcher struct {
+	wake     chan struct{}
+	inFlight sync.Map
+}
+
+func newOutboxDispatcher() *outboxDispatcher {
+	return &outboxDispatcher{
+		wake: make(chan struct{}, 1),
+	}
+}
+
+func (h *Handler) RunOutboxDispatcher(ctx context.Context) {
+	ticker := time.NewTicker(outboxPollInterval)
+	defer ticker.Stop()
+
+	cleanup := time.NewTicker(outboxCleanupEvery)
+	defer cleanup.Stop()
+
+	logger.Log.Infow("outbox dispatcher started")
+
+	for {
+		h.dispatchOutbox(ctx)
+
+		select {
+		case <-ctx.Done():
+			logger.Log.Infow("outbox dispatcher stopped")
+			return
+		case <-ticker.C:
+		case <-h.outbox.wake:
+		case <-cleanup.C:
+			deleted, err := h.outboxService.CleanupSent(ctx, outboxSentRetain)
+			if err != nil {
+				logger.Log.Errorw("failed to cleanup sent outbox jobs",
+					"err", err,
+				)
+				continue
+			}
+			logger.Log.Infow("sent outbox jobs cleaned up",
+				"deleted", deleted,
+			)
+		}
+	}
+}
+
+func (h *Handler) wakeOutbox() {
+	select {
+	case h.outbox.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (h *Handler) dispatchOutbox(ctx context.Context) {
+	jobs, err := h.outboxService.Claim(ctx, outboxBatchSize, outboxLease)
+	if err != nil {
+		logger.Log.Errorw("failed to claim outbox jobs",
+			"err", err,
+		)
+		return
+	}
+
+	for _, job := range jobs {
+		if _, busy := h.outbox.inFlight.LoadOrStore(job.ID, struct{}{}); busy {
+			continue
+		}
+
+		if job.Params == nil {
+			h.completeOutboxJob(job, errors.New("outbox params are unreadable"))
+			continue
+		}
+
+		params := tgbotapi.Params(job.Params)
+		h.sendQueue.enqueue(sendJob{
+			chatID: job.ChatID,
+			op:     "telegram.outbox_" + job.Method,
+			fn: func() error {
+				_, err := h.bot.MakeRequest(job.Method, params)
+				return err
+			},
+			fields: []any{
+				"outbox_id", job.ID,
+				"order_id", job.OrderID,
+			},
+			maxAttempts: maxTelegramRetryAttempts,
+			onDone: func(err error) {
+				h.completeOutboxJob(job, err)
+			},
+		})
+	}
+}
+
+func (h *Handler) completeOutboxJob(job domain.OutboxJob, err error) {
+	defer h.outbox.inFlight.Delete(job.ID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), outboxOpTimeout)
+	defer cancel()
+
+	if err == nil {
+		h.outboxService.MarkSent(ctx, job.ID)
+		return
+	}
+
+	wrapped := wrapTelegramErr("telegram.outbox_"+job.Method, err)
+
+	if isPermanentTelegramErr(wrapped) || job.Attempts >= outboxMaxAttempts {
+		logger.Log.Errorw("outbox job failed permanently",
+			"outbox_id", job.ID,
+			"order_id", job.OrderID,
+			"chat_id", job.ChatID,
+			"attempts", job.Attempts,
+			"err", wrapped,
+		)
+		h.outboxService.MarkFailed(ctx, job.ID, wrapped.Error())
+
+		if job.ChatID > 0 {
+			h.handleUndeliverable(job.ChatID, wrapped)
+		}
+		return
+	}
+
+	next := time.Now().Add(outboxBackoff(job.Attempts))
+
+	logger.Log.Warnw("outbox job rescheduled",
+		"outbox_id", job.ID,
+		"order_id", job.OrderID,
+		"attempts", job.Attempts,
+		"next_attempt_at", next,
+		"err", wrapped,
+	)
+	h.outboxService.MarkRetry(ctx, job.ID, next, wrapped.Error())
+}
+
+// Shutdown waits for queued sends and hands outbox jobs that did not make it
+// back to the table so the next process picks them up immediately.
+func (h *Handler) Shutdown(ctx context.Context) {
+	if n := h.expertWaves.flush(ctx); n > 0 {
+		logger.Log.Infow("postponed expert notifications sent",
+			"orders", n,
+		)
+	}
+
+	if h.sendQueue.drain(ctx) {
+		logger.Log.Infow("send queue drained")
+	} else {
+		logger.Log.Warnw("send queue drain timed out",
+			"pending", h.sendQueue.Depth(),
+		)
+	}
+
+	var ids []int64
+	h.outbox.inFlight.Range(func(key, _ any) bool {
+		ids = append(ids, key.(int64))
+		return true
+	})
+	if len(ids) == 0 {
+		return
+	}
+
+	releaseCtx, cancel := context.WithTimeout(context.Background(), outboxOpTimeout)
+	defer cancel()
+
+	if err := h.outboxService.Release(releaseCtx, ids); err != nil {
+		logger.Log.Errorw("failed to release outbox jobs",
+			"count", len(ids),
+			"err", err,
+		)
+		return
+	}
+
+	logger.Log.Infow("unsent outbox jobs released",
+		"count", len(ids),
+	)
+}
+
+func outboxBackoff(attempts int) time.Duration {
+	d := outboxBaseBackoff
+	for i := 1; i < attempts; i++ {
+		d *= 2
+		if d >= outboxMaxBackoff {
+			return outboxMaxBackoff
+		}
+	}
+	return d
+}
+
+func isPermanentTelegramErr(err error) bool {
+	var tgErr *apperr.TelegramError
+	if !errors.As(err, &tgErr) {
+		return false
+	}
+	return tgErr.Code >= 400 && tgErr.Code < 500 && tgErr.Code != 429
+}
+
+func relayIdempotencyKey(msg *tgbotapi.Message) string {
+	return fmt.Sprintf("relay:%d:%d", msg.Chat.ID, msg.MessageID)
+}
+
+func (h *Handler) OutboxCommand(ctx context.Context, msg *tgbotapi.Message) {
+	chatID := msg.Chat.ID
+
+	jobs, err := h.outboxService.GetStuck(ctx, outboxStuckAfter, outboxStuckLimit)
+	if err != nil {
+		logger.Log.Errorw("failed to get stuck outbox jobs",
+			"chat_id", chatID,
+			"err", err,
+		)
+		return
+	}
+
+	text := h.text.OutboxEmptyText
+	if len(jobs) > 0 {
+		var b strings.Builder
+		b.WriteString(h.text.OutboxStuckHeader)
+		for _, job := range jobs {
+			lastErr := "-"
+			if job.LastError != nil {
+				lastErr = *job.LastError
+			}
+			orderID := "-"
+			if job.OrderID != nil {
+				orderID = fmt.Sprint(*job.OrderID)
+			}
+			b.WriteString(fmt.Sprintf(
+				h.text.OutboxStuckLineTemplate,
+				job.ID,
+				job.Status,
+				orderID,
+				job.ChatID,
+				job.Method,
+				job.Attempts,
+				job.CreatedAt.Format("02.01 15:04"),
+				html.EscapeString(lastErr),
+			))
+		}
+		text = b.String()
+	}
+
+	message := tgbotapi.NewMessage(chatID, text)
+	message.ParseMode = tgbotapi.ModeHTML
+
+	h.post(message, "telegram.send_outbox_report", nil,
+		"chat_id", chatID,
+	)
+}
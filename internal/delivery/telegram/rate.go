@@ -2,7 +2,6 @@ package telegram
 
 import (
 	"context"
-	"strings"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/m4xvel/monetych_bot/internal/logger"
@@ -11,6 +10,7 @@ import (
 func (h *Handler) handleRateSelect(
 	ctx context.Context,
 	cb *tgbotapi.CallbackQuery,
+	payload RateSelectPayload,
 ) {
 	chatID := cb.Message.Chat.ID
 	messageID := cb.Message.MessageID
@@ -21,49 +21,15 @@ func (h *Handler) handleRateSelect(
 		"callback_data", cb.Data,
 	)
 
-	parts := strings.Split(cb.Data, ":")
-	if len(parts) != 2 {
-		logger.Log.Warnw("invalid rate callback data",
-			"chat_id", chatID,
-			"data", cb.Data,
-		)
-		return
-	}
-
-	tokenCallback := parts[1]
-
-	var payload RateSelectPayload
-
-	if err := h.callbackTokenService.Consume(
-		ctx,
-		tokenCallback,
-		"rate",
-		&payload,
-	); err != nil {
-		if isInvalidToken(err) {
-			logger.Log.Warnw("invalid rate callback token",
-				"chat_id", chatID,
-				"data", cb.Data,
-				"err", err,
-			)
-			return
-		}
-		logger.Log.Errorw("failed to consume rate callback token",
-			"chat_id", chatID,
-			"err", err,
-		)
-		return
-	}
-
 	rate := payload.Rate
 	orderID := payload.OrderID
 
-	if err := h.callbackTokenService.DeleteByActionAndOrderID(
+	if err := h.invalidateCallbacks(
 		ctx,
-		"rate",
+		surfaceRate,
 		orderID,
 	); err != nil {
-		logger.Log.Errorw("failed to delete rate callbacks",
+		logger.Log.Errorw("failed to invalidate rate callbacks",
 			"order_id", orderID,
 			"err", err,
 		)
@@ -84,6 +50,7 @@ func (h *Handler) handleRateSelect(
 			"rate", rate,
 			"err", err,
 		)
+		releaseCallback(ctx)
 		return
 	}
 
@@ -102,18 +69,17 @@ func (h *Handler) handleRateSelect(
 		return
 	}
 
-	if _, err := h.bot.Send(
-		tgbotapi.NewEditMessageText(
-			chatID,
-			messageID,
-			h.text.WriteReviewText,
-		),
-	); err != nil {
-		wrapped := wrapTelegramErr("telegram.edit_write_review_prompt", err)
-		logger.Log.Errorw("failed to prompt user to write review",
-			"chat_id", chatID,
-			"order_id", orderID,
-			"err", wrapped,
-		)
+	edit := tgbotapi.NewEditMessageText(
+		chatID,
+		messageID,
+		h.text.WriteReviewText,
+	)
+	if markup, ok := h.anonymousReviewMarkup(ctx, chatID, orderID); ok {
+		edit.ReplyMarkup = &markup
 	}
+
+	h.post(edit, "telegram.edit_write_review_prompt", nil,
+		"chat_id", chatID,
+		"order_id", orderID,
+	)
 }
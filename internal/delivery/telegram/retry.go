@@ -14,10 +14,6 @@ func retryOnRateLimit(op string, fn func() error, fields ...any) error {
 	return retryOnRateLimitWithAttempts(op, maxTelegramRetryAttempts, fn, fields...)
 }
 
-func retryOnRateLimitForever(op string, fn func() error, fields ...any) error {
-	return retryOnRateLimitWithAttempts(op, 0, fn, fields...)
-}
-
 func retryOnRateLimitWithAttempts(
 	op string,
 	maxAttempts int,
@@ -45,6 +41,7 @@ func retryOnRateLimitWithAttempts(
 				keyvals = append(keyvals, fields...)
 			}
 
+			telegramRateLimited.Inc(op)
 			logger.Log.Warnw("rate limited, retrying request", keyvals...)
 			time.Sleep(time.Duration(retryAfter) * time.Second)
 		}
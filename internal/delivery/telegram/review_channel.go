@@ -0,0 +1,224 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/m4xvel/monetych_bot/internal/apperr"
+	"github.com/m4xvel/monetych_bot/internal/domain"
+	"github.com/m4xvel/monetych_bot/internal/logger"
+)
+
+type ReviewAnonymousPayload struct {
+	ChatID  int64 `json:"chat_id"`
+	OrderID int   `json:"order_id"`
+}
+
+func (h *Handler) postReviewToChannel(
+	ctx context.Context,
+	reviewID int,
+) {
+	if h.reviewsChannelID == 0 {
+		return
+	}
+
+	post, err := h.reviewChannelPost(ctx, reviewID)
+	if err != nil {
+		logger.Log.Errorw("failed to build review channel post",
+			"review_id", reviewID,
+			"err", err,
+		)
+		return
+	}
+
+	sent, err := h.send(ctx, tgbotapi.NewMessage(h.reviewsChannelID, post))
+	if err != nil {
+		wrapped := wrapTelegramErr("telegram.post_review_channel", err)
+		logger.Log.Errorw("failed to post review to channel",
+			"review_id", reviewID,
+			"channel_id", h.reviewsChannelID,
+			"err", wrapped,
+		)
+		return
+	}
+
+	if err := h.reviewService.SetChannelPost(
+		ctx,
+		reviewID,
+		h.reviewsChannelID,
+		sent.MessageID,
+	); err != nil {
+		logger.Log.Errorw("failed to save review channel post",
+			"review_id", reviewID,
+			"message_id", sent.MessageID,
+			"err", err,
+		)
+		return
+	}
+
+	logger.Log.Infow("review posted to channel",
+		"review_id", reviewID,
+		"message_id", sent.MessageID,
+	)
+}
+
+func (h *Handler) reviewChannelPost(
+	ctx context.Context,
+	reviewID int,
+) (string, error) {
+	review, err := h.reviewService.GetByID(ctx, reviewID)
+	if err != nil {
+		return "", err
+	}
+
+	order, err := h.orderService.GetOrderByID(ctx, review.OrderID)
+	if err != nil {
+		return "", err
+	}
+
+	author := order.UserNameAtPurchase
+	if review.IsAnonymous || author == "" {
+		author = h.text.ReviewAnonymousAuthor
+	}
+
+	text := ""
+	if review.Text != nil {
+		text = *review.Text
+	}
+
+	date := ""
+	if review.PublishedAt != nil {
+		date = review.PublishedAt.Format("02.01.2006")
+	}
+
+	return h.textDynamic.ReviewChannelPost(
+		review.Rating,
+		order.GameNameAtPurchase,
+		order.GameTypeNameAtPurchase,
+		text,
+		author,
+		date,
+	), nil
+}
+
+// hideChannelPostAuthor rewrites an already posted review once its author
+// asked to stay anonymous.
+func (h *Handler) hideChannelPostAuthor(ctx context.Context, review domain.Review) {
+	if review.ChannelChatID == nil || review.ChannelMessageID == nil {
+		return
+	}
+
+	post, err := h.reviewChannelPost(ctx, review.ID)
+	if err != nil {
+		logger.Log.Errorw("failed to build anonymous review channel post",
+			"review_id", review.ID,
+			"err", err,
+		)
+		return
+	}
+
+	h.post(
+		tgbotapi.NewEditMessageText(
+			*review.ChannelChatID,
+			*review.ChannelMessageID,
+			post,
+		),
+		"telegram.edit_review_channel_post",
+		nil,
+		"review_id", review.ID,
+		"message_id", *review.ChannelMessageID,
+	)
+}
+
+func (h *Handler) anonymousReviewMarkup(
+	ctx context.Context,
+	chatID int64,
+	orderID int,
+) (tgbotapi.InlineKeyboardMarkup, bool) {
+	token, err := reviewAnonymousAction.Create(
+		ctx,
+		h,
+		ReviewAnonymousPayload{
+			ChatID:  chatID,
+			OrderID: orderID,
+		},
+	)
+	if err != nil {
+		logger.Log.Errorw("failed to create review anonymous callback token",
+			"chat_id", chatID,
+			"order_id", orderID,
+			"err", err,
+		)
+		return tgbotapi.InlineKeyboardMarkup{}, false
+	}
+
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(
+				h.text.ReviewAnonymousButtonText,
+				reviewAnonymousAction.Data(token),
+			),
+		),
+	), true
+}
+
+func (h *Handler) handleReviewAnonymous(
+	ctx context.Context,
+	cb *tgbotapi.CallbackQuery,
+	payload ReviewAnonymousPayload,
+) {
+	chatID := cb.Message.Chat.ID
+
+	review, err := h.reviewService.SetAnonymous(ctx, payload.OrderID)
+	switch {
+	case errors.Is(err, apperr.ErrConflict):
+		// The review was rejected and will never be shown.
+		logger.Log.Infow("anonymous button pressed for a rejected review",
+			"chat_id", chatID,
+			"order_id", payload.OrderID,
+		)
+		h.answerCallback(cb, h.text.ReviewAnonymousRejectedToast)
+		h.removeReviewAnonymousButton(chatID, cb.Message.MessageID)
+		return
+	case err != nil:
+		logger.Log.Warnw("failed to mark review anonymous",
+			"chat_id", chatID,
+			"order_id", payload.OrderID,
+			"err", err,
+		)
+		releaseCallback(ctx)
+		h.answerCallback(cb, "")
+		return
+	}
+
+	logger.Log.Infow("review marked anonymous",
+		"chat_id", chatID,
+		"order_id", payload.OrderID,
+		"status", review.Status,
+	)
+
+	if review.Status == domain.ReviewPublished {
+		h.answerCallback(cb, h.text.ReviewAnonymousPublishedToast)
+		h.hideChannelPostAuthor(ctx, *review)
+	} else {
+		h.answerCallback(cb, h.text.ReviewAnonymousToast)
+	}
+
+	h.removeReviewAnonymousButton(chatID, cb.Message.MessageID)
+}
+
+func (h *Handler) removeReviewAnonymousButton(chatID int64, messageID int) {
+	h.post(
+		tgbotapi.NewEditMessageReplyMarkup(
+			chatID,
+			messageID,
+			tgbotapi.InlineKeyboardMarkup{
+				InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{},
+			},
+		),
+		"telegram.remove_review_anonymous_button",
+		nil,
+		"chat_id", chatID,
+	)
+}
@@ -0,0 +1,434 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/m4xvel/monetych_bot/internal/apperr"
+	"github.com/m4xvel/monetych_bot/internal/domain"
+	"github.com/m4xvel/monetych_bot/internal/logger"
+)
+
+const moderationQueueLimit = 20
+
+type ReviewModerationPayload struct {
+	ReviewID int `json:"review_id"`
+	OrderID  int `json:"order_id"`
+	Reason   int `json:"reason,omitempty"`
+}
+
+func (h *Handler) submitReviewForModeration(
+	ctx context.Context,
+	reviewID int,
+) {
+	review, err := h.reviewService.GetByID(ctx, reviewID)
+	if err != nil {
+		logger.Log.Errorw("failed to get review for moderation",
+			"review_id", reviewID,
+			"err", err,
+		)
+		return
+	}
+
+	h.sendModerationCard(ctx, review)
+}
+
+func (h *Handler) sendModerationCard(
+	ctx context.Context,
+	review *domain.Review,
+) {
+	supportChatID := h.supportService.GetSupport().ChatID
+
+	order, err := h.orderService.GetOrderByID(ctx, review.OrderID)
+	if err != nil {
+		logger.Log.Errorw("failed to get order for review moderation",
+			"review_id", review.ID,
+			"order_id", review.OrderID,
+			"err", err,
+		)
+		return
+	}
+
+	text := ""
+	if review.Text != nil {
+		text = *review.Text
+	}
+
+	markup, ok := h.moderationMarkup(ctx, review.ID, review.OrderID)
+	if !ok {
+		return
+	}
+
+	msg := tgbotapi.NewMessage(
+		supportChatID,
+		h.textDynamic.ReviewModerationCard(
+			review.ID,
+			order.ID,
+			order.Token,
+			review.Rating,
+			text,
+		),
+	)
+	msg.ReplyMarkup = markup
+
+	dropTokens := func(error) {
+		if err := h.invalidateCallbacks(
+			ctx,
+			surfaceReviewModeration,
+			review.OrderID,
+		); err != nil {
+			logger.Log.Errorw("failed to invalidate review moderation callbacks",
+				"review_id", review.ID,
+				"err", err,
+			)
+		}
+	}
+
+	h.post(msg, "telegram.send_review_moderation", dropTokens,
+		"review_id", review.ID,
+	)
+
+	logger.Log.Infow("review sent to moderation",
+		"review_id", review.ID,
+		"order_id", review.OrderID,
+	)
+}
+
+func (h *Handler) moderationMarkup(
+	ctx context.Context,
+	reviewID int,
+	orderID int,
+) (tgbotapi.InlineKeyboardMarkup, bool) {
+	payload := ReviewModerationPayload{
+		ReviewID: reviewID,
+		OrderID:  orderID,
+	}
+
+	approveToken, err := reviewApproveAction.Create(ctx, h, payload)
+	if err != nil {
+		logger.Log.Errorw("failed to create review approve callback token",
+			"review_id", reviewID,
+			"err", err,
+		)
+		return tgbotapi.InlineKeyboardMarkup{}, false
+	}
+
+	rejectToken, err := reviewRejectAction.Create(ctx, h, payload)
+	if err != nil {
+		logger.Log.Errorw("failed to create review reject callback token",
+			"review_id", reviewID,
+			"err", err,
+		)
+		return tgbotapi.InlineKeyboardMarkup{}, false
+	}
+
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(
+				h.text.ReviewApproveButtonText,
+				reviewApproveAction.Data(approveToken),
+			),
+			tgbotapi.NewInlineKeyboardButtonData(
+				h.text.ReviewRejectButtonText,
+				reviewRejectAction.Data(rejectToken),
+			),
+		),
+	), true
+}
+
+func (h *Handler) ModerationCommand(ctx context.Context, msg *tgbotapi.Message) {
+	chatID := msg.Chat.ID
+
+	reviews, err := h.reviewService.GetPendingModeration(ctx, moderationQueueLimit)
+	if err != nil {
+		logger.Log.Errorw("failed to get reviews pending moderation",
+			"chat_id", chatID,
+			"err", err,
+		)
+		return
+	}
+
+	if len(reviews) == 0 {
+		h.post(
+			tgbotapi.NewMessage(chatID, h.text.ModerationQueueEmptyText),
+			"telegram.send_moderation_empty",
+			nil,
+			"chat_id", chatID,
+		)
+		return
+	}
+
+	for i := range reviews {
+		if err := h.invalidateCallbacks(
+			ctx,
+			surfaceReviewModeration,
+			reviews[i].OrderID,
+		); err != nil {
+			logger.Log.Errorw("failed to invalidate review moderation callbacks",
+				"review_id", reviews[i].ID,
+				"err", err,
+			)
+		}
+		h.sendModerationCard(ctx, &reviews[i])
+	}
+}
+
+func (h *Handler) handleReviewApprove(
+	ctx context.Context,
+	cb *tgbotapi.CallbackQuery,
+	payload ReviewModerationPayload,
+) {
+	if !h.isModerationCallback(ctx, cb, payload) {
+		return
+	}
+
+	if err := h.reviewService.Approve(
+		ctx,
+		payload.ReviewID,
+		cb.From.ID,
+	); err != nil {
+		h.handleModerationError(ctx, cb, payload, err)
+		return
+	}
+
+	h.answerCallback(cb, "")
+	h.closeModerationCard(
+		cb,
+		h.textDynamic.ReviewApprovedStatus(moderatorLabel(cb.From)),
+	)
+	h.notifyReviewAuthor(ctx, payload.OrderID, h.text.ReviewApprovedUserText)
+	h.postReviewToChannel(ctx, payload.ReviewID)
+}
+
+func (h *Handler) handleReviewReject(
+	ctx context.Context,
+	cb *tgbotapi.CallbackQuery,
+	payload ReviewModerationPayload,
+) {
+	if !h.isModerationCallback(ctx, cb, payload) {
+		return
+	}
+	h.answerCallback(cb, "")
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for i, reason := range h.text.ReviewRejectReasons {
+		token, err := reviewRejectReasonAction.Create(
+			ctx,
+			h,
+			ReviewModerationPayload{
+				ReviewID: payload.ReviewID,
+				OrderID:  payload.OrderID,
+				Reason:   i,
+			},
+		)
+		if err != nil {
+			logger.Log.Errorw("failed to create review reject reason callback token",
+				"review_id", payload.ReviewID,
+				"err", err,
+			)
+			continue
+		}
+
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(
+				reason,
+				reviewRejectReasonAction.Data(token),
+			),
+		))
+	}
+
+	backToken, err := reviewModerationBackAction.Create(ctx, h, payload)
+	if err != nil {
+		logger.Log.Errorw("failed to create review moderation back callback token",
+			"review_id", payload.ReviewID,
+			"err", err,
+		)
+	} else {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(
+				h.text.BackButtonText,
+				reviewModerationBackAction.Data(backToken),
+			),
+		))
+	}
+
+	h.editModerationMarkup(cb, tgbotapi.NewInlineKeyboardMarkup(rows...))
+}
+
+func (h *Handler) handleReviewRejectReason(
+	ctx context.Context,
+	cb *tgbotapi.CallbackQuery,
+	payload ReviewModerationPayload,
+) {
+	if !h.isModerationCallback(ctx, cb, payload) {
+		return
+	}
+
+	if payload.Reason < 0 || payload.Reason >= len(h.text.ReviewRejectReasons) {
+		logger.Log.Warnw("review reject reason out of range",
+			"review_id", payload.ReviewID,
+			"reason", payload.Reason,
+		)
+		h.answerCallback(cb, "")
+		return
+	}
+	reason := h.text.ReviewRejectReasons[payload.Reason]
+
+	if err := h.reviewService.Reject(
+		ctx,
+		payload.ReviewID,
+		cb.From.ID,
+		reason,
+	); err != nil {
+		h.handleModerationError(ctx, cb, payload, err)
+		return
+	}
+
+	h.answerCallback(cb, "")
+	h.closeModerationCard(
+		cb,
+		h.textDynamic.ReviewRejectedStatus(reason, moderatorLabel(cb.From)),
+	)
+	h.notifyReviewAuthor(
+		ctx,
+		payload.OrderID,
+		h.textDynamic.ReviewRejectedUserText(reason),
+	)
+}
+
+func (h *Handler) handleReviewModerationBack(
+	ctx context.Context,
+	cb *tgbotapi.CallbackQuery,
+	payload ReviewModerationPayload,
+) {
+	if !h.isModerationCallback(ctx, cb, payload) {
+		return
+	}
+	h.answerCallback(cb, "")
+
+	markup, ok := h.moderationMarkup(ctx, payload.ReviewID, payload.OrderID)
+	if !ok {
+		return
+	}
+
+	h.editModerationMarkup(cb, markup)
+}
+
+// isModerationCallback drops presses from outside the support chat and
+// retires the other buttons of the card, whatever the outcome.
+func (h *Handler) isModerationCallback(
+	ctx context.Context,
+	cb *tgbotapi.CallbackQuery,
+	payload ReviewModerationPayload,
+) bool {
+	if cb.Message.Chat.ID != h.supportService.GetSupport().ChatID {
+		logger.Log.Warnw("review moderation callback outside support chat",
+			"chat_id", cb.Message.Chat.ID,
+			"review_id", payload.ReviewID,
+		)
+		h.answerCallback(cb, "")
+		return false
+	}
+
+	if err := h.invalidateCallbacks(
+		ctx,
+		surfaceReviewModeration,
+		payload.OrderID,
+	); err != nil {
+		logger.Log.Errorw("failed to invalidate review moderation callbacks",
+			"review_id", payload.ReviewID,
+			"err", err,
+		)
+	}
+
+	return true
+}
+
+func (h *Handler) handleModerationError(
+	ctx context.Context,
+	cb *tgbotapi.CallbackQuery,
+	payload ReviewModerationPayload,
+	err error,
+) {
+	if errors.Is(err, apperr.ErrConflict) {
+		logger.Log.Infow("review already moderated",
+			"review_id", payload.ReviewID,
+		)
+		h.answerCallback(cb, h.text.ReviewAlreadyModeratedToast)
+		h.editModerationMarkup(cb, tgbotapi.InlineKeyboardMarkup{
+			InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{},
+		})
+		return
+	}
+
+	logger.Log.Errorw("failed to moderate review",
+		"review_id", payload.ReviewID,
+		"err", err,
+	)
+	h.answerCallback(cb, "")
+
+	if markup, ok := h.moderationMarkup(ctx, payload.ReviewID, payload.OrderID); ok {
+		h.editModerationMarkup(cb, markup)
+	}
+}
+
+func (h *Handler) closeModerationCard(
+	cb *tgbotapi.CallbackQuery,
+	status string,
+) {
+	edit := tgbotapi.NewEditMessageText(
+		cb.Message.Chat.ID,
+		cb.Message.MessageID,
+		cb.Message.Text+status,
+	)
+
+	h.post(edit, "telegram.edit_review_moderation", nil,
+		"chat_id", cb.Message.Chat.ID,
+	)
+}
+
+func (h *Handler) editModerationMarkup(
+	cb *tgbotapi.CallbackQuery,
+	markup tgbotapi.InlineKeyboardMarkup,
+) {
+	edit := tgbotapi.NewEditMessageReplyMarkup(
+		cb.Message.Chat.ID,
+		cb.Message.MessageID,
+		markup,
+	)
+
+	h.post(edit, "telegram.edit_review_moderation_markup", nil,
+		"chat_id", cb.Message.Chat.ID,
+	)
+}
+
+func (h *Handler) notifyReviewAuthor(
+	ctx context.Context,
+	orderID int,
+	text string,
+) {
+	if !h.notifyReviewAuthors {
+		return
+	}
+
+	order, err := h.orderService.GetOrderByID(ctx, orderID)
+	if err != nil {
+		logger.Log.Errorw("failed to get order for review author notification",
+			"order_id", orderID,
+			"err", err,
+		)
+		return
+	}
+
+	h.post(tgbotapi.NewMessage(order.UserChatID, text), "telegram.notify_review_author", nil,
+		"order_id", orderID,
+	)
+}
+
+func moderatorLabel(u *tgbotapi.User) string {
+	if u.UserName != "" {
+		return "@" + u.UserName
+	}
+	return u.FirstName
+}
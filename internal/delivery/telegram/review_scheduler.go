@@ -0,0 +1,116 @@
+package telegram
+
+import (
+	"context"
+	"time"
+
+	"github.com/m4xvel/monetych_bot/internal/domain"
+	"github.com/m4xvel/monetych_bot/internal/logger"
+)
+
+const reviewSchedulerBatch = 50
+
+// PublishStaleReviews publishes ratings whose author never wrote the text
+// and moves them out of the writing review state.
+func (h *Handler) PublishStaleReviews(ctx context.Context) {
+	if h.reviewAutoPublishAfter <= 0 {
+		return
+	}
+
+	before := time.Now().Add(-h.reviewAutoPublishAfter)
+
+	reviews, err := h.reviewService.PublishRatedBefore(
+		ctx,
+		before,
+		reviewSchedulerBatch,
+	)
+	if err != nil {
+		logger.Log.Errorw("failed to auto publish reviews",
+			"before", before,
+			"err", err,
+		)
+		return
+	}
+
+	for _, review := range reviews {
+		h.leaveWritingReviewState(ctx, review)
+		h.postReviewToChannel(ctx, review.ID)
+	}
+}
+
+func (h *Handler) leaveWritingReviewState(
+	ctx context.Context,
+	review domain.Review,
+) {
+	order, err := h.orderService.GetOrderByID(ctx, review.OrderID)
+	if err != nil || order == nil {
+		logger.Log.Errorw("failed to get order for auto published review",
+			"review_id", review.ID,
+			"order_id", review.OrderID,
+			"err", err,
+		)
+		return
+	}
+
+	state, err := h.stateService.GetStateByChatID(ctx, order.UserChatID)
+	if err != nil {
+		return
+	}
+
+	if state.State != domain.StateWritingReview ||
+		state.ReviewID == nil ||
+		*state.ReviewID != review.ID {
+		return
+	}
+
+	if err := h.stateService.SetStateIdle(ctx, order.UserChatID); err != nil {
+		logger.Log.Errorw("failed to set idle state after review auto publish",
+			"review_id", review.ID,
+			"user_chat_id", order.UserChatID,
+			"err", err,
+		)
+	}
+}
+
+// RemindUnratedOrders asks once more for a rating of orders completed
+// longer than the reminder delay ago.
+func (h *Handler) RemindUnratedOrders(ctx context.Context) {
+	if h.reviewReminderDelay <= 0 {
+		return
+	}
+
+	before := time.Now().Add(-h.reviewReminderDelay)
+
+	orders, err := h.orderService.ClaimReviewReminders(
+		ctx,
+		before,
+		reviewSchedulerBatch,
+	)
+	if err != nil {
+		logger.Log.Errorw("failed to claim review reminders",
+			"before", before,
+			"err", err,
+		)
+		return
+	}
+
+	for _, order := range orders {
+		if err := h.invalidateCallbacks(
+			ctx,
+			surfaceRate,
+			order.ID,
+		); err != nil {
+			logger.Log.Errorw("failed to invalidate rate callbacks",
+				"order_id", order.ID,
+				"err", err,
+			)
+		}
+
+		h.sendRatePrompt(ctx, order.UserChatID, order.ID, h.text.ReviewReminderText)
+
+		logger.Log.Infow("review reminder sent",
+			"order_id", order.ID,
+			"user_chat_id", order.UserChatID,
+		)
+	}
+}
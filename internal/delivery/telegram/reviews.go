@@ -0,0 +1,282 @@
+package telegram
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/m4xvel/monetych_bot/internal/logger"
+)
+
+const (
+	reviewsPageSize      = 5
+	reviewsTextMaxRunes  = 300
+	reviewsGameButtonRow = 2
+)
+
+// ReviewsPagePayload selects a page of published reviews; a zero GameID
+// shows reviews for all games.
+type ReviewsPagePayload struct {
+	ChatID int64 `json:"chat_id"`
+	GameID int   `json:"game_id"`
+	Page   int   `json:"page"`
+}
+
+func encodeReviewsPage(p ReviewsPagePayload) []int64 {
+	return []int64{p.ChatID, int64(p.GameID), int64(p.Page)}
+}
+
+func decodeReviewsPage(f []int64) (ReviewsPagePayload, bool) {
+	if len(f) != 3 || f[2] < 0 {
+		return ReviewsPagePayload{}, false
+	}
+	return ReviewsPagePayload{
+		ChatID: f[0],
+		GameID: int(f[1]),
+		Page:   int(f[2]),
+	}, true
+}
+
+func (h *Handler) handleReviewsCommand(
+	ctx context.Context,
+	msg *tgbotapi.Message,
+) {
+	chatID := msg.Chat.ID
+
+	logger.Log.Infow("reviews command initiated",
+		"chat_id", chatID,
+	)
+
+	text, markup, err := h.renderReviewsPage(ctx, ReviewsPagePayload{
+		ChatID: chatID,
+	})
+	if err != nil {
+		logger.Log.Errorw("failed to render reviews page",
+			"chat_id", chatID,
+			"err", err,
+		)
+		return
+	}
+
+	message := tgbotapi.NewMessage(chatID, text)
+	message.ReplyMarkup = markup
+
+	h.post(message, "telegram.send_reviews", nil,
+		"chat_id", chatID,
+	)
+}
+
+func (h *Handler) handleReviewsPage(
+	ctx context.Context,
+	cb *tgbotapi.CallbackQuery,
+	payload ReviewsPagePayload,
+) {
+	chatID := cb.Message.Chat.ID
+	h.answerCallback(cb, "")
+
+	text, markup, err := h.renderReviewsPage(ctx, payload)
+	if err != nil {
+		logger.Log.Errorw("failed to render reviews page",
+			"chat_id", chatID,
+			"game_id", payload.GameID,
+			"page", payload.Page,
+			"err", err,
+		)
+		return
+	}
+
+	edit := tgbotapi.NewEditMessageText(chatID, cb.Message.MessageID, text)
+	edit.ReplyMarkup = &markup
+
+	h.post(edit, "telegram.edit_reviews", nil,
+		"chat_id", chatID,
+	)
+}
+
+func (h *Handler) renderReviewsPage(
+	ctx context.Context,
+	payload ReviewsPagePayload,
+) (string, tgbotapi.InlineKeyboardMarkup, error) {
+	var markup tgbotapi.InlineKeyboardMarkup
+
+	gameName := ""
+	if payload.GameID != 0 {
+		game, err := h.gameService.GetGameByID(payload.GameID)
+		if err != nil {
+			return "", markup, err
+		}
+		gameName = game.Name
+	}
+
+	stats, err := h.reviewService.GetPublishedStats(ctx, payload.GameID)
+	if err != nil {
+		return "", markup, err
+	}
+
+	pages := (stats.Count + reviewsPageSize - 1) / reviewsPageSize
+	page := payload.Page
+	if page >= pages {
+		page = max(pages-1, 0)
+	}
+
+	reviews, err := h.reviewService.ListPublished(
+		ctx,
+		payload.GameID,
+		reviewsPageSize,
+		page*reviewsPageSize,
+	)
+	if err != nil {
+		return "", markup, err
+	}
+
+	parts := []string{h.textDynamic.ReviewsSummary(
+		gameName,
+		stats.Average,
+		stats.Count,
+		stats.ByRating,
+	)}
+
+	if len(reviews) == 0 {
+		parts = append(parts, h.text.ReviewsEmptyText)
+	}
+
+	for _, r := range reviews {
+		author := r.AuthorName
+		if author == "" {
+			author = h.text.ReviewAnonymousAuthor
+		}
+
+		text := ""
+		if r.Text != nil {
+			text = truncateRunes(*r.Text, reviewsTextMaxRunes)
+		}
+
+		date := ""
+		if r.PublishedAt != nil {
+			date = r.PublishedAt.Format("02.01.2006")
+		}
+
+		parts = append(parts, h.textDynamic.ReviewsListItem(
+			r.Rating,
+			r.GameName,
+			r.GameTypeName,
+			text,
+			author,
+			date,
+		))
+	}
+
+	if pages > 1 {
+		parts = append(parts, h.textDynamic.ReviewsPageFooter(page+1, pages))
+	}
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+
+	var nav []tgbotapi.InlineKeyboardButton
+	if page > 0 {
+		btn, err := h.reviewsPageButton(ctx, h.text.ReviewsPrevButtonText, ReviewsPagePayload{
+			ChatID: payload.ChatID,
+			GameID: payload.GameID,
+			Page:   page - 1,
+		})
+		if err != nil {
+			return "", markup, err
+		}
+		nav = append(nav, btn)
+	}
+	if page+1 < pages {
+		btn, err := h.reviewsPageButton(ctx, h.text.ReviewsNextButtonText, ReviewsPagePayload{
+			ChatID: payload.ChatID,
+			GameID: payload.GameID,
+			Page:   page + 1,
+		})
+		if err != nil {
+			return "", markup, err
+		}
+		nav = append(nav, btn)
+	}
+	if len(nav) > 0 {
+		rows = append(rows, nav)
+	}
+
+	filters, err := h.reviewsFilterRows(ctx, payload)
+	if err != nil {
+		return "", markup, err
+	}
+	rows = append(rows, filters...)
+
+	markup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+	return strings.Join(parts, "\n\n"), markup, nil
+}
+
+// reviewsFilterRows offers every game except the one already selected,
+// plus "all games" while a filter is active.
+func (h *Handler) reviewsFilterRows(
+	ctx context.Context,
+	payload ReviewsPagePayload,
+) ([][]tgbotapi.InlineKeyboardButton, error) {
+	games, err := h.gameService.GetAllGames()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(games, func(i, j int) bool { return games[i].ID < games[j].ID })
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+
+	if payload.GameID != 0 {
+		btn, err := h.reviewsPageButton(ctx, h.text.ReviewsAllGamesButtonText, ReviewsPagePayload{
+			ChatID: payload.ChatID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(btn))
+	}
+
+	var row []tgbotapi.InlineKeyboardButton
+	for _, g := range games {
+		if g.ID == payload.GameID {
+			continue
+		}
+
+		btn, err := h.reviewsPageButton(ctx, g.Name, ReviewsPagePayload{
+			ChatID: payload.ChatID,
+			GameID: g.ID,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		row = append(row, btn)
+		if len(row) == reviewsGameButtonRow {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func (h *Handler) reviewsPageButton(
+	ctx context.Context,
+	label string,
+	payload ReviewsPagePayload,
+) (tgbotapi.InlineKeyboardButton, error) {
+	token, err := reviewsPageAction.Create(ctx, h, payload)
+	if err != nil {
+		return tgbotapi.InlineKeyboardButton{}, err
+	}
+	return tgbotapi.NewInlineKeyboardButtonData(label, reviewsPageAction.Data(token)), nil
+}
+
+func truncateRunes(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return strings.TrimSpace(string(runes[:limit])) + "…"
+}
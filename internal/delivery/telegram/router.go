@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"strings"
-	"sync"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/m4xvel/monetych_bot/internal/logger"
@@ -12,21 +11,23 @@ import (
 
 type HandlerFunc func(ctx context.Context, msg *tgbotapi.Message)
 type CallbackHandlerFunc func(ctx context.Context, cb *tgbotapi.CallbackQuery)
+type ChatMemberHandlerFunc func(ctx context.Context, upd *tgbotapi.ChatMemberUpdated)
 
 type Router struct {
 	commandHandlers  map[string]HandlerFunc
 	callbackHandlers map[string]CallbackHandlerFunc
 	messageHandler   HandlerFunc
+	myChatMember     ChatMemberHandlerFunc
+	middlewares      []Middleware
 
-	mu    sync.Mutex
-	locks map[string]struct{}
+	dedup DedupStore
 }
 
-func NewRouter() *Router {
+func NewRouter(dedup DedupStore) *Router {
 	return &Router{
 		commandHandlers:  make(map[string]HandlerFunc),
 		callbackHandlers: make(map[string]CallbackHandlerFunc),
-		locks:            make(map[string]struct{}),
+		dedup:            dedup,
 	}
 }
 
@@ -42,7 +43,26 @@ func (r *Router) RegisterMessageHandler(handler HandlerFunc) {
 	r.messageHandler = handler
 }
 
+func (r *Router) RegisterMyChatMemberHandler(handler ChatMemberHandlerFunc) {
+	r.myChatMember = handler
+}
+
+// Use appends middlewares; the first one added runs outermost.
+func (r *Router) Use(mw ...Middleware) {
+	r.middlewares = append(r.middlewares, mw...)
+}
+
 func (r *Router) Route(ctx context.Context, upd tgbotapi.Update) {
+	next := r.dispatch
+	for i := len(r.middlewares) - 1; i >= 0; i-- {
+		next = r.middlewares[i](next)
+	}
+	next(ctx, NewUpdateContext(upd))
+}
+
+func (r *Router) dispatch(ctx context.Context, uc *UpdateContext) {
+	upd := uc.Update
+
 	switch {
 
 	case upd.Message != nil:
@@ -89,10 +109,7 @@ func (r *Router) Route(ctx context.Context, upd tgbotapi.Update) {
 			"username", cb.From.UserName,
 		)
 
-		r.mu.Lock()
-		if _, exists := r.locks[lockKey]; exists {
-			r.mu.Unlock()
-
+		if !r.dedup.Acquire(lockKey) {
 			logger.Log.Warnw("callback ignored (already handled)",
 				"user_id", cb.From.ID,
 				"data", cb.Data,
@@ -100,21 +117,43 @@ func (r *Router) Route(ctx context.Context, upd tgbotapi.Update) {
 
 			return
 		}
-		r.locks[lockKey] = struct{}{}
-		r.mu.Unlock()
+
+		release := func() { r.dedup.Release(lockKey) }
+		ctx = context.WithValue(ctx, callbackReleaseKey{}, release)
 
 		for prefix, h := range r.callbackHandlers {
 			if strings.HasPrefix(cb.Data, prefix) {
+				defer func() {
+					if rec := recover(); rec != nil {
+						release()
+						panic(rec)
+					}
+				}()
 				h(ctx, cb)
 				return
 			}
 		}
 
+		release()
+
 		logger.Log.Warnw("no callback handler",
 			"user_id", cb.From.ID,
 			"data", cb.Data,
 		)
 
+	case upd.MyChatMember != nil:
+		member := upd.MyChatMember
+
+		logger.Log.Infow("my chat member update received",
+			"chat_id", member.Chat.ID,
+			"old_status", member.OldChatMember.Status,
+			"new_status", member.NewChatMember.Status,
+		)
+
+		if r.myChatMember != nil {
+			r.myChatMember(ctx, member)
+		}
+
 	default:
 		logger.Log.Debugw("unknown update received")
 	}
@@ -27,16 +27,15 @@ func (h *Handler) SearchCommand(ctx context.Context, msg *tgbotapi.Message) {
 			"chat_id", chatID,
 		)
 
-		if _, err := h.bot.Send(tgbotapi.NewMessage(
-			chatID,
-			h.text.SearchTokenPromptText,
-		)); err != nil {
-			wrapped := wrapTelegramErr("telegram.send_search_prompt", err)
-			logger.Log.Errorw("failed to prompt token for search",
-				"chat_id", chatID,
-				"err", wrapped,
-			)
-		}
+		h.post(
+			tgbotapi.NewMessage(
+				chatID,
+				h.text.SearchTokenPromptText,
+			),
+			"telegram.send_search_prompt",
+			nil,
+			"chat_id", chatID,
+		)
 		return
 	}
 
@@ -46,16 +45,15 @@ func (h *Handler) SearchCommand(ctx context.Context, msg *tgbotapi.Message) {
 			"chat_id", chatID,
 		)
 
-		if _, err := h.bot.Send(tgbotapi.NewMessage(
-			chatID,
-			h.text.SearchNotFoundText,
-		)); err != nil {
-			wrapped := wrapTelegramErr("telegram.send_search_not_found", err)
-			logger.Log.Errorw("failed to send not found message",
-				"chat_id", chatID,
-				"err", wrapped,
-			)
-		}
+		h.post(
+			tgbotapi.NewMessage(
+				chatID,
+				h.text.SearchNotFoundText,
+			),
+			"telegram.send_search_not_found",
+			nil,
+			"chat_id", chatID,
+		)
 		return
 	}
 
@@ -74,9 +72,17 @@ func (h *Handler) SearchCommand(ctx context.Context, msg *tgbotapi.Message) {
 	}
 
 	summary := h.formatOrderSummary(orderFull)
-	if err := h.sendSearchSummary(ctx, chatID, msg.MessageID, summary, mediaCount, orderFull.Order.ID); err != nil {
-		return
+
+	fraudHits, err := h.fraudHitService.GetByOrder(ctx, orderFull.Order.ID)
+	if err != nil {
+		logger.Log.Errorw("failed to get fraud hits for search",
+			"chat_id", chatID,
+			"order_id", orderFull.Order.ID,
+			"err", err,
+		)
 	}
+	summary += h.formatFraudHits(fraudHits)
+	h.sendSearchSummary(ctx, chatID, msg.MessageID, summary, mediaCount, orderFull.Order.ID)
 
 	if len(orderFull.Messages) > 0 {
 		chatLines := make([]string, 0, len(orderFull.Messages))
@@ -87,15 +93,10 @@ func (h *Handler) SearchCommand(ctx context.Context, msg *tgbotapi.Message) {
 		for _, chunk := range h.buildChatChunks(chatLines, maxTelegramMessageLen) {
 			response := tgbotapi.NewMessage(chatID, chunk)
 			response.ParseMode = tgbotapi.ModeHTML
-			if _, err := h.bot.Send(response); err != nil {
-				wrapped := wrapTelegramErr("telegram.send_search_result", err)
-				logger.Log.Errorw("failed to send order search result chunk",
-					"chat_id", chatID,
-					"order_id", orderFull.Order.ID,
-					"err", wrapped,
-				)
-				return
-			}
+			h.post(response, "telegram.send_search_result", nil,
+				"chat_id", chatID,
+				"order_id", orderFull.Order.ID,
+			)
 		}
 	}
 }
@@ -171,6 +172,14 @@ func (h *Handler) formatOrderSummary(orderFull *domain.OrderFull) string {
 		} else {
 			builder.WriteString(h.text.SearchExpertActiveNo)
 		}
+		if rating := h.expertRatingText(orderFull.Expert.ID); rating != "" {
+			builder.WriteString(fmt.Sprintf(
+				h.text.SearchExpertRatingLineTemplate,
+				rating,
+			))
+		} else {
+			builder.WriteString(h.text.SearchExpertNoRatingText)
+		}
 		builder.WriteString("\n")
 	}
 
@@ -190,15 +199,7 @@ func (h *Handler) formatOrderSummary(orderFull *domain.OrderFull) string {
 }
 
 func (h *Handler) formatChatMessage(chatMessage domain.ChatMessage) string {
-	var sender string
-	switch chatMessage.SenderRole {
-	case domain.SenderUser:
-		sender = h.text.SenderUserLabel
-	case domain.SenderExpert:
-		sender = h.text.SenderExpertLabel
-	default:
-		sender = h.text.SenderSystemLabel
-	}
+	sender := h.senderLabel(chatMessage.SenderRole)
 
 	var builder strings.Builder
 
@@ -228,6 +229,10 @@ func (h *Handler) formatChatMessage(chatMessage domain.ChatMessage) string {
 		builder.WriteString(h.text.ChatOtherLine)
 	}
 
+	if chatMessage.IsRedacted {
+		builder.WriteString(h.text.ChatRedactedLine)
+	}
+
 	builder.WriteString("\n")
 	return builder.String()
 }
@@ -239,7 +244,7 @@ func (h *Handler) sendSearchSummary(
 	summary string,
 	mediaCount int,
 	orderID int,
-) error {
+) {
 	parts := splitByLineLimit(summary, maxTelegramMessageLen)
 	var showMediaToken string
 
@@ -249,10 +254,10 @@ func (h *Handler) sendSearchSummary(
 		if i == 0 {
 			response.ReplyToMessageID = replyTo
 			if mediaCount > 0 {
-				token, err := h.callbackTokenService.Create(
+				token, err := showMediaAction.Create(
 					ctx,
-					"show_media",
-					&SearchPayload{
+					h,
+					SearchPayload{
 						ChatID:  chatID,
 						OrderID: orderID,
 					},
@@ -266,7 +271,7 @@ func (h *Handler) sendSearchSummary(
 					showMediaToken = token
 					showMediaButton := tgbotapi.NewInlineKeyboardButtonData(
 						fmt.Sprintf(h.text.SearchShowMediaButtonTemplate, mediaCount),
-						"show_media:"+token,
+						showMediaAction.Data(token),
 					)
 					response.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
 						tgbotapi.NewInlineKeyboardRow(showMediaButton),
@@ -275,18 +280,13 @@ func (h *Handler) sendSearchSummary(
 			}
 		}
 
-		if _, err := h.bot.Send(response); err != nil {
-			wrapped := wrapTelegramErr("telegram.send_search_result", err)
-			logger.Log.Errorw("failed to send order search result",
-				"chat_id", chatID,
-				"order_id", orderID,
-				"err", wrapped,
-			)
-			if i == 0 && showMediaToken != "" {
-				if err := h.callbackTokenService.Delete(
+		var onError func(error)
+		if i == 0 && showMediaToken != "" {
+			onError = func(error) {
+				if err := showMediaAction.Delete(
 					ctx,
+					h,
 					showMediaToken,
-					"show_media",
 				); err != nil {
 					logger.Log.Errorw("failed to cleanup show media callback token",
 						"chat_id", chatID,
@@ -295,11 +295,13 @@ func (h *Handler) sendSearchSummary(
 					)
 				}
 			}
-			return err
 		}
-	}
 
-	return nil
+		h.post(response, "telegram.send_search_result", onError,
+			"chat_id", chatID,
+			"order_id", orderID,
+		)
+	}
 }
 
 func (h *Handler) formatOrderStatus(
@@ -1,43 +1,380 @@
 package telegram
 
 import (
+	"context"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/m4xvel/monetych_bot/internal/logger"
+	"github.com/m4xvel/monetych_bot/internal/ratelimit"
 )
 
-const copyMessageQueueSize = 500
+const (
+	globalSendsPerSecond = 30
+	privateSendsPerSec   = 1
+	privateSendsBurst    = 5
+	groupSendsPerMinute  = 20
+	laneIdleTimeout      = time.Minute
+	sendQueueDepthWarn   = 500
+	sendWaitTimeout      = 10 * time.Second
+)
 
 type sendJob struct {
-	op     string
-	fn     func() error
-	fields []any
+	ctx         context.Context
+	chatID      int64
+	op          string
+	fn          func() error
+	fields      []any
+	onError     func(err error)
+	onDone      func(err error)
+	maxAttempts int
+	done        chan error
+}
+
+type sendLane struct {
+	jobs        []sendJob
+	wake        chan struct{}
+	budget      *ratelimit.Bucket
+	pausedUntil time.Time
+}
+
+// sendScheduler keeps one FIFO lane per chat so a rate-limited chat only
+// delays its own messages, while a shared bucket keeps the bot under the
+// global Telegram limit.
+type sendScheduler struct {
+	global *ratelimit.Bucket
+
+	mu    sync.Mutex
+	lanes map[int64]*sendLane
+	depth atomic.Int64
+
+	lastSuccess atomic.Int64
+}
+
+func newSendScheduler() *sendScheduler {
+	return &sendScheduler{
+		global: ratelimit.NewBucket(globalSendsPerSecond, globalSendsPerSecond),
+		lanes:  make(map[int64]*sendLane),
+	}
 }
 
-type sendQueue struct {
-	jobs chan sendJob
+func (s *sendScheduler) enqueue(job sendJob) {
+	s.mu.Lock()
+	lane, ok := s.lanes[job.chatID]
+	if !ok {
+		lane = &sendLane{
+			wake:   make(chan struct{}, 1),
+			budget: chatBudget(job.chatID),
+		}
+		s.lanes[job.chatID] = lane
+		go s.runLane(job.chatID, lane)
+	}
+	lane.jobs = append(lane.jobs, job)
+	s.mu.Unlock()
+
+	if depth := s.depth.Add(1); depth == sendQueueDepthWarn {
+		logger.Log.Warnw("telegram send queue is growing",
+			"depth", depth,
+		)
+	}
+
+	select {
+	case lane.wake <- struct{}{}:
+	default:
+	}
 }
 
-func newSendQueue(buffer int) *sendQueue {
-	q := &sendQueue{
-		jobs: make(chan sendJob, buffer),
+// do runs fn in the chat lane and waits for the result until ctx is done.
+// A job whose caller gave up is dropped before it runs, so a late send can't
+// land after the caller has already handled the failure. Calls without a
+// chat only pass the global budget and run on the caller's goroutine.
+func (s *sendScheduler) do(
+	ctx context.Context,
+	chatID int64,
+	op string,
+	fn func() error,
+) error {
+	if chatID == 0 {
+		s.global.Wait()
+		err := retryOnRateLimit(op, fn)
+		if err == nil {
+			s.markSuccess()
+		}
+		return err
+	}
+
+	done := make(chan error, 1)
+	s.enqueue(sendJob{
+		ctx:         ctx,
+		chatID:      chatID,
+		op:          op,
+		fn:          fn,
+		maxAttempts: maxTelegramRetryAttempts,
+		done:        done,
+	})
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
 	}
-	go q.run()
-	return q
 }
 
-func (q *sendQueue) enqueue(job sendJob) {
-	q.jobs <- job
+func (s *sendScheduler) Depth() int {
+	return int(s.depth.Load())
 }
 
-func (q *sendQueue) run() {
-	for job := range q.jobs {
-		if err := retryOnRateLimitForever(job.op, job.fn, job.fields...); err != nil {
-			wrapped := wrapTelegramErr(job.op, err)
-			keyvals := []any{"op", job.op}
-			if len(job.fields) > 0 {
-				keyvals = append(keyvals, job.fields...)
+func (s *sendScheduler) markSuccess() {
+	s.lastSuccess.Store(time.Now().UnixNano())
+}
+
+// LastSuccess is when a Telegram call last succeeded; zero if none has.
+func (s *sendScheduler) LastSuccess() time.Time {
+	ns := s.lastSuccess.Load()
+	if ns == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, ns)
+}
+
+func (s *sendScheduler) drain(ctx context.Context) bool {
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	for s.Depth() > 0 {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+		}
+	}
+
+	return true
+}
+
+func (s *sendScheduler) runLane(chatID int64, lane *sendLane) {
+	idle := time.NewTimer(laneIdleTimeout)
+	defer idle.Stop()
+
+	for {
+		s.mu.Lock()
+		if len(lane.jobs) == 0 {
+			s.mu.Unlock()
+
+			idle.Reset(laneIdleTimeout)
+
+			select {
+			case <-lane.wake:
+				continue
+			case <-idle.C:
+				s.mu.Lock()
+				if len(lane.jobs) == 0 {
+					delete(s.lanes, chatID)
+					s.mu.Unlock()
+					return
+				}
+				s.mu.Unlock()
+				continue
 			}
-			keyvals = append(keyvals, "err", wrapped)
-			logger.Log.Errorw("failed to process telegram send job", keyvals...)
 		}
+
+		job := lane.jobs[0]
+		lane.jobs[0] = sendJob{}
+		lane.jobs = lane.jobs[1:]
+		s.mu.Unlock()
+
+		s.process(lane, job)
+		s.depth.Add(-1)
+	}
+}
+
+func (s *sendScheduler) process(lane *sendLane, job sendJob) {
+	var err error
+
+	for attempt := 1; ; attempt++ {
+		if wait := time.Until(lane.pausedUntil); wait > 0 {
+			time.Sleep(wait)
+		}
+		lane.budget.Wait()
+
+		if job.ctx != nil && job.ctx.Err() != nil {
+			err = job.ctx.Err()
+			break
+		}
+
+		s.global.Wait()
+
+		err = job.fn()
+		if err == nil {
+			s.markSuccess()
+			break
+		}
+
+		retryAfter, ok := retryAfterSeconds(err)
+		if !ok || (job.maxAttempts > 0 && attempt >= job.maxAttempts) {
+			break
+		}
+
+		lane.pausedUntil = time.Now().Add(time.Duration(retryAfter) * time.Second)
+		telegramRateLimited.Inc(job.op)
+
+		keyvals := []any{
+			"op", job.op,
+			"chat_id", job.chatID,
+			"retry_after", retryAfter,
+			"attempt", attempt,
+		}
+		keyvals = append(keyvals, job.fields...)
+		logger.Log.Warnw("rate limited, chat lane paused", keyvals...)
+	}
+
+	if job.done != nil {
+		job.done <- err
+		return
+	}
+
+	if job.onDone != nil {
+		go job.onDone(err)
+		return
+	}
+
+	if err != nil {
+		wrapped := wrapTelegramErr(job.op, err)
+		keyvals := []any{"op", job.op}
+		if len(job.fields) > 0 {
+			keyvals = append(keyvals, job.fields...)
+		}
+		keyvals = append(keyvals, "err", wrapped)
+		logger.Log.Errorw("failed to process telegram send job", keyvals...)
+		if job.onError != nil {
+			go job.onError(wrapped)
+		}
+	}
+}
+
+func chatBudget(chatID int64) *ratelimit.Bucket {
+	if chatID < 0 {
+		return ratelimit.NewBucket(groupSendsPerMinute/60.0, groupSendsPerMinute)
+	}
+	return ratelimit.NewBucket(privateSendsPerSec, privateSendsBurst)
+}
+
+// post queues c in its chat lane and returns without waiting. The lane logs
+// a failure under op along with fields and then calls onError, if set.
+func (h *Handler) post(c tgbotapi.Chattable, op string, onError func(error), fields ...any) {
+	h.sendQueue.enqueue(sendJob{
+		chatID: chattableChatID(c),
+		op:     op,
+		fn: func() error {
+			_, err := h.bot.Request(c)
+			return err
+		},
+		fields:      fields,
+		onError:     onError,
+		maxAttempts: maxTelegramRetryAttempts,
+	})
+}
+
+// send waits for the sent message, so it is only for callers that need it.
+// The wait is capped at sendWaitTimeout to keep a throttled chat from
+// holding the dispatcher worker.
+func (h *Handler) send(ctx context.Context, c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	ctx, cancel := context.WithTimeout(ctx, sendWaitTimeout)
+	defer cancel()
+
+	var sent tgbotapi.Message
+	err := h.sendQueue.do(ctx, chattableChatID(c), "telegram.send", func() error {
+		var err error
+		sent, err = h.bot.Send(c)
+		return err
+	})
+	return sent, err
+}
+
+// request is for calls that don't target a chat, such as callback answers;
+// those skip the lanes and only wait for the global budget.
+func (h *Handler) request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), sendWaitTimeout)
+	defer cancel()
+
+	var resp *tgbotapi.APIResponse
+	err := h.sendQueue.do(ctx, chattableChatID(c), "telegram.request", func() error {
+		var err error
+		resp, err = h.bot.Request(c)
+		return err
+	})
+	return resp, err
+}
+
+func (h *Handler) makeRequest(
+	ctx context.Context,
+	endpoint string,
+	params tgbotapi.Params,
+) (*tgbotapi.APIResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, sendWaitTimeout)
+	defer cancel()
+
+	var resp *tgbotapi.APIResponse
+	err := h.sendQueue.do(ctx, paramsChatID(params), "telegram."+endpoint, func() error {
+		var err error
+		resp, err = h.bot.MakeRequest(endpoint, params)
+		return err
+	})
+	return resp, err
+}
+
+func (h *Handler) SendQueueDepth() int {
+	return h.sendQueue.Depth()
+}
+
+func (h *Handler) TelegramLastSuccess() time.Time {
+	return h.sendQueue.LastSuccess()
+}
+
+// ProbeTelegram calls getMe, which fails once the token is revoked or the
+// API is unreachable.
+func (h *Handler) ProbeTelegram() error {
+	return h.sendQueue.do(context.Background(), 0, "telegram.get_me", func() error {
+		_, err := h.bot.GetMe()
+		return err
+	})
+}
+
+func chattableChatID(c tgbotapi.Chattable) int64 {
+	switch v := c.(type) {
+	case tgbotapi.MessageConfig:
+		return v.ChatID
+	case tgbotapi.PhotoConfig:
+		return v.ChatID
+	case tgbotapi.VideoConfig:
+		return v.ChatID
+	case tgbotapi.VideoNoteConfig:
+		return v.ChatID
+	case tgbotapi.DocumentConfig:
+		return v.ChatID
+	case tgbotapi.VoiceConfig:
+		return v.ChatID
+	case tgbotapi.CopyMessageConfig:
+		return v.ChatID
+	case tgbotapi.EditMessageTextConfig:
+		return v.ChatID
+	case tgbotapi.EditMessageReplyMarkupConfig:
+		return v.ChatID
+	case tgbotapi.DeleteMessageConfig:
+		return v.ChatID
+	default:
+		return 0
+	}
+}
+
+func paramsChatID(params tgbotapi.Params) int64 {
+	id, err := strconv.ParseInt(params["chat_id"], 10, 64)
+	if err != nil {
+		return 0
 	}
+	return id
 }
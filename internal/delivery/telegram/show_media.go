@@ -19,6 +19,7 @@ const (
 func (h *Handler) handleShowMedia(
 	ctx context.Context,
 	cb *tgbotapi.CallbackQuery,
+	payload SearchPayload,
 ) {
 	chatID := cb.Message.Chat.ID
 
@@ -30,56 +31,14 @@ func (h *Handler) handleShowMedia(
 		},
 	}
 
-	if _, err := h.bot.Send(edit); err != nil {
-		wrapped := wrapTelegramErr("telegram.remove_show_media_keyboard", err)
-		logger.Log.Errorw("failed to remove show media keyboard",
-			"chat_id", chatID,
-			"err", wrapped,
-		)
-	}
+	h.post(edit, "telegram.remove_show_media_keyboard", nil,
+		"chat_id", chatID,
+	)
 
 	logger.Log.Infow("show media selected",
 		"chat_id", chatID,
 	)
 
-	parts := strings.Split(cb.Data, ":")
-	if len(parts) != 2 {
-		logger.Log.Warnw("invalid type callback data",
-			"chat_id", chatID,
-			"data", cb.Data,
-		)
-		return
-	}
-
-	tokenCallback := parts[1]
-
-	var payload SearchPayload
-
-	if err := h.callbackTokenService.Consume(
-		ctx,
-		tokenCallback,
-		"show_media",
-		&payload,
-	); err != nil {
-		if isInvalidToken(err) {
-			logger.Log.Warnw("invalid show media callback token",
-				"chat_id", chatID,
-				"data", cb.Data,
-				"err", err,
-			)
-			return
-		}
-		logger.Log.Errorw("failed to consume show media callback token",
-			"chat_id", chatID,
-			"err", err,
-		)
-		return
-	}
-
-	if payload.ChatID != cb.From.ID {
-		return
-	}
-
 	orderID := payload.OrderID
 
 	orderFull, err := h.orderService.FindByID(context.Background(), orderID)
@@ -112,43 +71,19 @@ func (h *Handler) handleShowMedia(
 			photoReply.ReplyToMessageID = cb.Message.MessageID
 			photoReply.Caption = h.formatMediaCaption(chatMessage)
 			photoReply.ParseMode = tgbotapi.ModeHTML
-			if err := retryOnRateLimit(
-				"telegram.send_media_photo",
-				func() error {
-					_, err := h.bot.Send(photoReply)
-					return err
-				},
+			h.post(photoReply, "telegram.send_media_photo", nil,
 				"chat_id", chatID,
 				"order_id", orderID,
-			); err != nil {
-				wrapped := wrapTelegramErr("telegram.send_media_photo", err)
-				logger.Log.Errorw("failed to send photo",
-					"chat_id", chatID,
-					"order_id", orderID,
-					"err", wrapped,
-				)
-			}
+			)
 		case domain.MessageVideo:
 			videoReply := tgbotapi.NewVideo(chatID, tgbotapi.FileID(fileID))
 			videoReply.ReplyToMessageID = cb.Message.MessageID
 			videoReply.Caption = h.formatMediaCaption(chatMessage)
 			videoReply.ParseMode = tgbotapi.ModeHTML
-			if err := retryOnRateLimit(
-				"telegram.send_media_video",
-				func() error {
-					_, err := h.bot.Send(videoReply)
-					return err
-				},
+			h.post(videoReply, "telegram.send_media_video", nil,
 				"chat_id", chatID,
 				"order_id", orderID,
-			); err != nil {
-				wrapped := wrapTelegramErr("telegram.send_media_video", err)
-				logger.Log.Errorw("failed to send video",
-					"chat_id", chatID,
-					"order_id", orderID,
-					"err", wrapped,
-				)
-			}
+			)
 		case domain.MessageVideoNote:
 			length, _ := mediaInt(chatMessage.Media, "length")
 			videoNoteReply := tgbotapi.NewVideoNote(
@@ -157,64 +92,28 @@ func (h *Handler) handleShowMedia(
 				tgbotapi.FileID(fileID),
 			)
 			videoNoteReply.ReplyToMessageID = cb.Message.MessageID
-			if err := retryOnRateLimit(
-				"telegram.send_media_video_note",
-				func() error {
-					_, err := h.bot.Send(videoNoteReply)
-					return err
-				},
+			h.post(videoNoteReply, "telegram.send_media_video_note", nil,
 				"chat_id", chatID,
 				"order_id", orderID,
-			); err != nil {
-				wrapped := wrapTelegramErr("telegram.send_media_video_note", err)
-				logger.Log.Errorw("failed to send video note",
-					"chat_id", chatID,
-					"order_id", orderID,
-					"err", wrapped,
-				)
-			}
+			)
 		case domain.MessageDocument:
 			documentReply := tgbotapi.NewDocument(chatID, tgbotapi.FileID(fileID))
 			documentReply.ReplyToMessageID = cb.Message.MessageID
 			documentReply.Caption = h.formatMediaCaption(chatMessage)
 			documentReply.ParseMode = tgbotapi.ModeHTML
-			if err := retryOnRateLimit(
-				"telegram.send_media_document",
-				func() error {
-					_, err := h.bot.Send(documentReply)
-					return err
-				},
+			h.post(documentReply, "telegram.send_media_document", nil,
 				"chat_id", chatID,
 				"order_id", orderID,
-			); err != nil {
-				wrapped := wrapTelegramErr("telegram.send_media_document", err)
-				logger.Log.Errorw("failed to send document",
-					"chat_id", chatID,
-					"order_id", orderID,
-					"err", wrapped,
-				)
-			}
+			)
 		case domain.MessageVoice:
 			voiceReply := tgbotapi.NewVoice(chatID, tgbotapi.FileID(fileID))
 			voiceReply.ReplyToMessageID = cb.Message.MessageID
 			voiceReply.Caption = h.formatMediaCaption(chatMessage)
 			voiceReply.ParseMode = tgbotapi.ModeHTML
-			if err := retryOnRateLimit(
-				"telegram.send_media_voice",
-				func() error {
-					_, err := h.bot.Send(voiceReply)
-					return err
-				},
+			h.post(voiceReply, "telegram.send_media_voice", nil,
 				"chat_id", chatID,
 				"order_id", orderID,
-			); err != nil {
-				wrapped := wrapTelegramErr("telegram.send_media_voice", err)
-				logger.Log.Errorw("failed to send voice",
-					"chat_id", chatID,
-					"order_id", orderID,
-					"err", wrapped,
-				)
-			}
+			)
 		}
 
 		sentCount++
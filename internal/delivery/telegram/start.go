@@ -48,7 +48,7 @@ func (h *Handler) handleStartCommand(
 		scope := tgbotapi.NewBotCommandScopeChat(chatID)
 		cfg := tgbotapi.NewSetMyCommandsWithScope(scope, commands...)
 
-		if _, err := h.bot.Request(cfg); err != nil {
+		if _, err := h.request(cfg); err != nil {
 			wrapped := wrapTelegramErr("telegram.set_support_commands", err)
 			logger.Log.Errorw("failed to set support commands",
 				"chat_id", chatID,
@@ -62,13 +62,14 @@ func (h *Handler) handleStartCommand(
 	commands := []tgbotapi.BotCommand{
 		{Command: "start", Description: h.text.StartMenuText},
 		{Command: "catalog", Description: h.text.CatalogMenuText},
+		{Command: "reviews", Description: h.text.ReviewsMenuText},
 		{Command: "support", Description: h.text.SupportMenuText},
 	}
 
 	scope := tgbotapi.NewBotCommandScopeChat(chatID)
 	cfg := tgbotapi.NewSetMyCommandsWithScope(scope, commands...)
 
-	if _, err := h.bot.Request(cfg); err != nil {
+	if _, err := h.request(cfg); err != nil {
 		wrapped := wrapTelegramErr("telegram.set_user_commands", err)
 		logger.Log.Errorw("failed to set user commands",
 			"chat_id", chatID,
@@ -76,28 +77,31 @@ func (h *Handler) handleStartCommand(
 		)
 	}
 
-	if _, err := h.bot.SetChatMenuButton(tgbotapi.SetChatMenuButtonConfig{
-		ChatID: chatID,
-		MenuButton: tgbotapi.MenuButton{
-			Type: "commands",
+	h.sendQueue.enqueue(sendJob{
+		chatID: chatID,
+		op:     "telegram.set_chat_menu_button",
+		fn: func() error {
+			_, err := h.bot.SetChatMenuButton(tgbotapi.SetChatMenuButtonConfig{
+				ChatID: chatID,
+				MenuButton: tgbotapi.MenuButton{
+					Type: "commands",
+				},
+			})
+			return err
 		},
-	}); err != nil {
-		wrapped := wrapTelegramErr("telegram.set_chat_menu_button", err)
-		logger.Log.Errorw("failed to set chat menu button",
-			"chat_id", chatID,
-			"err", wrapped,
-		)
-	}
+		fields:      []any{"chat_id", chatID},
+		maxAttempts: maxTelegramRetryAttempts,
+	})
 
 	accepted, _ := h.userPolicyAcceptancesService.IsAccepted(ctx, chatID)
 	if !accepted {
 		message := tgbotapi.NewMessage(chatID, h.textDynamic.HelloText())
 		message.ParseMode = "Markdown"
 		message.DisableWebPagePreview = true
-		token, err := h.callbackTokenService.Create(
+		token, err := acceptPrivacyAction.Create(
 			ctx,
-			"accept_privacy",
-			&AcceptPrivacySelectPayload{
+			h,
+			AcceptPrivacySelectPayload{
 				ChatID: chatID,
 			},
 		)
@@ -111,20 +115,15 @@ func (h *Handler) handleStartCommand(
 		message.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
 			tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(
 				h.text.AgreeButtonText,
-				"accept_privacy:"+token,
+				acceptPrivacyAction.Data(token),
 			)),
 		)
 
-		if _, err := h.bot.Send(message); err != nil {
-			wrapped := wrapTelegramErr("telegram.send_hello", err)
-			logger.Log.Errorw("failed to send hello message",
-				"chat_id", chatID,
-				"err", wrapped,
-			)
-			if err := h.callbackTokenService.Delete(
+		dropToken := func(error) {
+			if err := acceptPrivacyAction.Delete(
 				ctx,
+				h,
 				token,
-				"accept_privacy",
 			); err != nil {
 				logger.Log.Errorw("failed to cleanup accept privacy callback token",
 					"chat_id", chatID,
@@ -133,17 +132,20 @@ func (h *Handler) handleStartCommand(
 			}
 		}
 
-		return
-	}
-
-	if _, err := h.bot.Send(tgbotapi.NewMessage(chatID, h.textDynamic.HelloTextNotFirst())); err != nil {
-		wrapped := wrapTelegramErr("telegram.send_hello_not_first", err)
-		logger.Log.Errorw("failed to send hello message (not first)",
+		h.post(message, "telegram.send_hello", dropToken,
 			"chat_id", chatID,
-			"err", wrapped,
 		)
+
+		return
 	}
 
+	h.post(
+		tgbotapi.NewMessage(chatID, h.textDynamic.HelloTextNotFirst()),
+		"telegram.send_hello_not_first",
+		nil,
+		"chat_id", chatID,
+	)
+
 	if accepted {
 		h.handlerCatalogCommand(ctx, msg)
 	}
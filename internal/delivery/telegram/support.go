@@ -21,14 +21,9 @@ func (h *Handler) handlerSupportCommand(
 		fmt.Sprintf(h.text.SupportContactTemplate, supportInfo.ChatLink),
 	)
 
-	if _, err := h.bot.Send(message); err != nil {
-		wrapped := wrapTelegramErr("telegram.send_support_message", err)
-		logger.Log.Errorw("failed to send support message",
-			"chat_id", chatID,
-			"err", wrapped,
-		)
-		return
-	}
+	h.post(message, "telegram.send_support_message", nil,
+		"chat_id", chatID,
+	)
 
 	if err := h.stateService.SetStateIdle(ctx, chatID); err != nil {
 		logger.Log.Errorw("failed to set idle state after support command",
@@ -21,3 +21,11 @@ func isOrderAlreadyProcessed(err error) bool {
 func isInvalidToken(err error) bool {
 	return errors.Is(err, apperr.ErrInvalid)
 }
+
+func isExpiredToken(err error) bool {
+	return errors.Is(err, apperr.ErrExpired)
+}
+
+func isBotBlocked(err error) bool {
+	return apperr.IsBotBlocked(err)
+}
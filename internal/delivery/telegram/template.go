@@ -0,0 +1,264 @@
+package telegram
+
+import (
+	"context"
+	"strconv"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/m4xvel/monetych_bot/internal/domain"
+	"github.com/m4xvel/monetych_bot/internal/logger"
+	"github.com/m4xvel/monetych_bot/internal/usecase"
+)
+
+// TemplateSelectPayload is owned by the forum member who asked for the
+// template list; nobody else in the forum can send from it.
+type TemplateSelectPayload struct {
+	OrderID      int   `json:"order_id"`
+	TemplateID   int   `json:"template_id"`
+	TopicID      int64 `json:"topic_id"`
+	ThreadID     int64 `json:"thread_id"`
+	ExpertUserID int64 `json:"expert_user_id"`
+}
+
+func (h *Handler) handleTemplateCommand(
+	ctx context.Context,
+	msg *tgbotapi.Message,
+) {
+	if msg.MessageThreadID == 0 {
+		h.replyInThread(msg, h.text.TemplateOnlyInThread)
+		return
+	}
+
+	state, err := h.stateService.GetStateByThreadID(ctx, msg.MessageThreadID)
+	if err != nil {
+		logger.Log.Errorw("failed to get state by thread id for templates",
+			"thread_id", msg.MessageThreadID,
+			"err", err,
+		)
+		return
+	}
+	if state == nil || state.OrderID == nil || state.ExpertID == nil ||
+		!canExpertWrite(*state.OrderStatus) {
+		h.replyInThread(msg, h.text.TemplateOnlyInThread)
+		return
+	}
+
+	templates, err := h.responseTemplateService.ListForExpert(ctx, *state.ExpertID)
+	if err != nil {
+		logger.Log.Errorw("failed to list response templates",
+			"order_id", *state.OrderID,
+			"expert_id", *state.ExpertID,
+			"err", err,
+		)
+		return
+	}
+	if len(templates) == 0 {
+		h.replyInThread(msg, h.text.TemplatesEmptyText)
+		return
+	}
+
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(templates))
+	for _, tpl := range templates {
+		token, err := templateAction.Create(
+			ctx,
+			h,
+			TemplateSelectPayload{
+				OrderID:      *state.OrderID,
+				TemplateID:   tpl.ID,
+				TopicID:      msg.Chat.ID,
+				ThreadID:     msg.MessageThreadID,
+				ExpertUserID: msg.From.ID,
+			},
+		)
+		if err != nil {
+			logger.Log.Errorw("failed to create template callback token",
+				"order_id", *state.OrderID,
+				"template_id", tpl.ID,
+				"err", err,
+			)
+			continue
+		}
+
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(tpl.Title, templateAction.Data(token)),
+		))
+	}
+	if len(rows) == 0 {
+		return
+	}
+
+	message := tgbotapi.NewMessage(msg.Chat.ID, h.text.TemplatesListText)
+	message.MessageThreadID = msg.MessageThreadID
+	message.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+
+	orderID := *state.OrderID
+	dropTokens := func(error) {
+		if err := h.invalidateCallbacks(
+			ctx,
+			surfaceTemplates,
+			orderID,
+		); err != nil {
+			logger.Log.Errorw("failed to cleanup template callback tokens",
+				"order_id", orderID,
+				"err", err,
+			)
+		}
+	}
+
+	h.post(message, "telegram.send_templates_list", dropTokens,
+		"order_id", orderID,
+	)
+}
+
+func (h *Handler) handleTemplateSelect(
+	ctx context.Context,
+	cb *tgbotapi.CallbackQuery,
+	payload TemplateSelectPayload,
+) {
+	chatID := cb.Message.Chat.ID
+
+	if err := h.invalidateCallbacks(
+		ctx,
+		surfaceTemplates,
+		payload.OrderID,
+	); err != nil {
+		logger.Log.Errorw("failed to cleanup template callback tokens",
+			"order_id", payload.OrderID,
+			"err", err,
+		)
+	}
+
+	h.post(
+		tgbotapi.NewDeleteMessage(
+			chatID,
+			cb.Message.MessageID,
+		),
+		"telegram.delete_templates_list",
+		nil,
+		"chat_id", chatID,
+		"message_id", cb.Message.MessageID,
+	)
+
+	state, err := h.stateService.GetStateByThreadID(ctx, payload.ThreadID)
+	if err != nil || state == nil || state.OrderID == nil ||
+		*state.OrderID != payload.OrderID ||
+		!canExpertWrite(*state.OrderStatus) {
+		logger.Log.Warnw("template selected for inactive order",
+			"order_id", payload.OrderID,
+			"thread_id", payload.ThreadID,
+			"err", err,
+		)
+		h.answerCallback(cb, h.text.TemplateUnavailableToast)
+		return
+	}
+
+	expert, err := h.expertService.GetExpertByID(*state.ExpertID)
+	if err != nil || expert.TopicID != chatID {
+		logger.Log.Warnw("template selected outside the assigned expert forum",
+			"order_id", payload.OrderID,
+			"expert_id", *state.ExpertID,
+			"chat_id", chatID,
+			"user_id", cb.From.ID,
+			"err", err,
+		)
+		h.answerCallback(cb, h.text.TemplateUnavailableToast)
+		return
+	}
+
+	order, err := h.orderService.GetOrderByID(ctx, payload.OrderID)
+	if err != nil || order == nil {
+		logger.Log.Errorw("failed to get order for template",
+			"order_id", payload.OrderID,
+			"err", err,
+		)
+		releaseCallback(ctx)
+		return
+	}
+
+	values := usecase.TemplateValues{
+		Game:     order.GameNameAtPurchase,
+		GameType: order.GameTypeNameAtPurchase,
+		Token:    order.Token,
+		OrderID:  strconv.Itoa(order.ID),
+	}
+	if user, err := h.userService.GetByChatID(ctx, *state.UserChatID); err == nil && user != nil {
+		values.UserName = user.Name
+	}
+
+	tpl, text, err := h.responseTemplateService.Render(
+		ctx,
+		payload.TemplateID,
+		*state.ExpertID,
+		values,
+	)
+	if err != nil {
+		logger.Log.Errorw("failed to render response template",
+			"order_id", payload.OrderID,
+			"template_id", payload.TemplateID,
+			"err", err,
+		)
+		h.answerCallback(cb, h.text.TemplateUnavailableToast)
+		return
+	}
+
+	message := tgbotapi.NewMessage(payload.TopicID, text)
+	message.MessageThreadID = payload.ThreadID
+
+	sent, err := h.send(ctx, message)
+	if err != nil {
+		wrapped := wrapTelegramErr("telegram.send_template_to_thread", err)
+		logger.Log.Errorw("failed to send template to expert thread",
+			"order_id", payload.OrderID,
+			"template_id", tpl.ID,
+			"err", wrapped,
+		)
+		releaseCallback(ctx)
+		return
+	}
+
+	if err := h.orderChatMessageService.SaveExpertMessage(
+		ctx,
+		payload.OrderID,
+		*state.ExpertID,
+		sent.Chat.ID,
+		sent.MessageID,
+		domain.MessageText,
+		&text,
+		nil,
+		&domain.OutboxJob{
+			IdempotencyKey: relayIdempotencyKey(&sent),
+			OrderID:        state.OrderID,
+			ChatID:         *state.UserChatID,
+			Method:         "sendMessage",
+			Params: map[string]string{
+				"chat_id": int64PtrToStr(state.UserChatID),
+				"text":    text,
+			},
+		},
+	); err != nil {
+		logger.Log.Errorw("failed to save template message",
+			"order_id", payload.OrderID,
+			"template_id", tpl.ID,
+			"err", err,
+		)
+		return
+	}
+
+	h.wakeOutbox()
+
+	h.answerCallback(cb, h.text.TemplateSentToast)
+
+	logger.Log.Infow("response template queued to user",
+		"order_id", payload.OrderID,
+		"template_id", tpl.ID,
+	)
+}
+
+func (h *Handler) replyInThread(msg *tgbotapi.Message, text string) {
+	reply := tgbotapi.NewMessage(msg.Chat.ID, text)
+	reply.MessageThreadID = msg.MessageThreadID
+
+	h.post(reply, "telegram.send_thread_reply", nil,
+		"chat_id", msg.Chat.ID,
+	)
+}
@@ -3,7 +3,6 @@ package telegram
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/m4xvel/monetych_bot/internal/logger"
@@ -12,6 +11,7 @@ import (
 func (h *Handler) handleTypeSelect(
 	ctx context.Context,
 	cb *tgbotapi.CallbackQuery,
+	payload TypeSelectPayload,
 ) {
 	chatID := cb.Message.Chat.ID
 	h.answerCallback(cb, "")
@@ -20,44 +20,6 @@ func (h *Handler) handleTypeSelect(
 		"chat_id", chatID,
 	)
 
-	parts := strings.Split(cb.Data, ":")
-	if len(parts) != 2 {
-		logger.Log.Warnw("invalid type callback data",
-			"chat_id", chatID,
-			"data", cb.Data,
-		)
-		return
-	}
-
-	tokenCallback := parts[1]
-
-	var payload TypeSelectPayload
-
-	if err := h.callbackTokenService.Consume(
-		ctx,
-		tokenCallback,
-		"type",
-		&payload,
-	); err != nil {
-		if isInvalidToken(err) {
-			logger.Log.Warnw("invalid type callback token",
-				"chat_id", chatID,
-				"data", cb.Data,
-				"err", err,
-			)
-			return
-		}
-		logger.Log.Errorw("failed to consume type callback token",
-			"chat_id", chatID,
-			"err", err,
-		)
-		return
-	}
-
-	if payload.ChatID != cb.From.ID {
-		return
-	}
-
 	gameID := payload.GameID
 	gameTypeID := payload.TypeID
 
@@ -79,10 +41,10 @@ func (h *Handler) handleTypeSelect(
 		return
 	}
 
-	token, err := h.callbackTokenService.Create(
+	token, err := orderAction.Create(
 		ctx,
-		"order",
-		&OrderSelectPayload{
+		h,
+		OrderSelectPayload{
 			ChatID: chatID,
 			GameID: gameID,
 			TypeID: gameTypeID,
@@ -111,7 +73,7 @@ func (h *Handler) handleTypeSelect(
 
 	btn := tgbotapi.NewInlineKeyboardButtonData(
 		h.text.ContactText,
-		"order:"+token,
+		orderAction.Data(token),
 	)
 
 	markup := tgbotapi.NewInlineKeyboardMarkup(
@@ -120,17 +82,16 @@ func (h *Handler) handleTypeSelect(
 
 	edit.ReplyMarkup = &markup
 
-	if _, err := h.bot.Request(edit); err != nil {
-		wrapped := wrapTelegramErr("telegram.edit_type_message", err)
-		logger.Log.Errorw("failed to edit type selection message",
-			"chat_id", chatID,
-			"err", wrapped,
-		)
-		if err := h.callbackTokenService.Delete(ctx, token, "order"); err != nil {
+	dropToken := func(error) {
+		if err := orderAction.Delete(ctx, h, token); err != nil {
 			logger.Log.Errorw("failed to cleanup order callback token",
 				"chat_id", chatID,
 				"err", err,
 			)
 		}
 	}
+
+	h.post(edit, "telegram.edit_type_message", dropToken,
+		"chat_id", chatID,
+	)
 }
@@ -11,6 +11,7 @@ import (
 func (h *Handler) handleVerificationSelect(
 	ctx context.Context,
 	cb *tgbotapi.CallbackQuery,
+	payload VerificationSelectPayload,
 ) {
 	chatID := cb.Message.Chat.ID
 	h.answerCallback(cb, h.text.VerificationRequestSentToast)
@@ -20,56 +21,23 @@ func (h *Handler) handleVerificationSelect(
 		"callback_data", cb.Data,
 	)
 
-	parts := strings.Split(cb.Data, ":")
-	if len(parts) != 2 {
-		logger.Log.Warnw("invalid verification callback data",
-			"chat_id", chatID,
-			"data", cb.Data,
-		)
-		return
-	}
-
-	tokenCallback := parts[1]
-
-	var payload VerificationSelectPayload
-
-	if err := h.callbackTokenService.Consume(
-		ctx,
-		tokenCallback,
-		"verification",
-		&payload,
-	); err != nil {
-		if isInvalidToken(err) {
-			logger.Log.Warnw("invalid verification callback token",
-				"chat_id", chatID,
-				"data", cb.Data,
-				"err", err,
-			)
-			return
-		}
-		logger.Log.Errorw("failed to consume verification callback token",
-			"chat_id", chatID,
-			"err", err,
-		)
-		return
-	}
-
-	tokenVerify, err := h.callbackTokenService.Create(
+	tokenVerify, err := verifyAction.Create(
 		ctx,
-		"verify",
-		&payload,
+		h,
+		payload,
 	)
 	if err != nil {
 		logger.Log.Errorw("failed to create verify callback token",
 			"chat_id", chatID,
 			"err", err,
 		)
+		releaseCallback(ctx)
 		return
 	}
 
 	btn := tgbotapi.NewInlineKeyboardButtonData(
 		h.text.VerifyButtonText,
-		"verify:"+tokenVerify,
+		verifyAction.Data(tokenVerify),
 	)
 
 	markup := tgbotapi.NewInlineKeyboardMarkup(
@@ -82,17 +50,17 @@ func (h *Handler) handleVerificationSelect(
 	)
 	message.ReplyMarkup = &markup
 
-	if _, err := h.bot.Send(message); err != nil {
+	if _, err := h.send(ctx, message); err != nil {
 		wrapped := wrapTelegramErr("telegram.send_verification_message", err)
 		logger.Log.Errorw("failed to send verification message to user",
 			"chat_id", payload.UserChatID,
 			"order_id", payload.OrderID,
 			"err", wrapped,
 		)
-		if err := h.callbackTokenService.Delete(
+		if err := verifyAction.Delete(
 			ctx,
+			h,
 			tokenVerify,
-			"verify",
 		); err != nil {
 			logger.Log.Errorw("failed to cleanup verify callback token",
 				"chat_id", payload.UserChatID,
@@ -109,6 +77,7 @@ func (h *Handler) handleVerificationSelect(
 func (h *Handler) handleVerifySelect(
 	ctx context.Context,
 	cb *tgbotapi.CallbackQuery,
+	payload VerificationSelectPayload,
 ) {
 	chatID := cb.Message.Chat.ID
 	h.answerCallback(cb, h.text.VerificationRequestReceivedToast)
@@ -118,62 +87,21 @@ func (h *Handler) handleVerifySelect(
 		"callback_data", cb.Data,
 	)
 
-	parts := strings.Split(cb.Data, ":")
-	if len(parts) != 2 {
-		logger.Log.Warnw("invalid verify callback data",
-			"chat_id", chatID,
-			"data", cb.Data,
-		)
-		return
-	}
-
-	tokenCallback := parts[1]
-
-	var payload VerificationSelectPayload
-
-	if err := h.callbackTokenService.Consume(
-		ctx,
-		tokenCallback,
-		"verify",
-		&payload,
-	); err != nil {
-		if isInvalidToken(err) {
-			logger.Log.Warnw("invalid verify callback token",
-				"chat_id", chatID,
-				"data", cb.Data,
-				"err", err,
-			)
-			return
-		}
-		logger.Log.Errorw("failed to consume verify callback token",
-			"chat_id", chatID,
-			"err", err,
-		)
-		return
-	}
-
-	if payload.UserChatID != cb.From.ID {
-		logger.Log.Warnw("verify callback user mismatch",
-			"chat_id", chatID,
-			"user_chat_id", payload.UserChatID,
-		)
-		return
-	}
-
 	if err := h.userService.SetVerified(ctx, payload.UserChatID, true); err != nil {
 		logger.Log.Errorw("failed to set user verified",
 			"chat_id", chatID,
 			"err", err,
 		)
+		releaseCallback(ctx)
 		return
 	}
 
-	if err := h.callbackTokenService.DeleteByActionAndOrderID(
+	if err := h.invalidateCallbacks(
 		ctx,
-		"verification",
+		surfaceControlPanel,
 		payload.OrderID,
 	); err != nil {
-		logger.Log.Errorw("failed to delete verification callbacks",
+		logger.Log.Errorw("failed to invalidate control panel callbacks",
 			"order_id", payload.OrderID,
 			"err", err,
 		)
@@ -186,13 +114,9 @@ func (h *Handler) handleVerifySelect(
 	)
 	edit.ReplyMarkup = nil
 
-	if _, err := h.bot.Send(edit); err != nil {
-		wrapped := wrapTelegramErr("telegram.edit_verify_success", err)
-		logger.Log.Errorw("failed to edit verification message",
-			"chat_id", chatID,
-			"err", wrapped,
-		)
-	}
+	h.post(edit, "telegram.edit_verify_success", nil,
+		"chat_id", chatID,
+	)
 
 	order, err := h.orderService.GetOrderByID(ctx, payload.OrderID)
 	if err != nil || order == nil {
@@ -253,14 +177,10 @@ func (h *Handler) removeVerificationButton(
 		},
 	}
 
-	if _, err := h.bot.Send(edit); err != nil {
-		wrapped := wrapTelegramErr("telegram.remove_verification_button", err)
-		logger.Log.Errorw("failed to remove verification button",
-			"chat_id", msg.Chat.ID,
-			"message_id", msg.MessageID,
-			"err", wrapped,
-		)
-	}
+	h.post(edit, "telegram.remove_verification_button", nil,
+		"chat_id", msg.Chat.ID,
+		"message_id", msg.MessageID,
+	)
 }
 
 func stripVerificationButton(
@@ -276,7 +196,7 @@ func stripVerificationButton(
 	for _, row := range markup.InlineKeyboard {
 		newRow := make([]tgbotapi.InlineKeyboardButton, 0, len(row))
 		for _, btn := range row {
-			if btn.CallbackData != nil && strings.HasPrefix(*btn.CallbackData, "verification:") {
+			if btn.CallbackData != nil && strings.HasPrefix(*btn.CallbackData, verificationAction.Data("")) {
 				changed = true
 				continue
 			}
@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// AdminAuditEntry records one authenticated request to the admin API.
+// StatusCode is nil until the request has finished.
+type AdminAuditEntry struct {
+	ID         int64
+	Actor      string
+	Action     string
+	OrderID    *int
+	Method     string
+	Path       string
+	StatusCode *int
+	RemoteIP   string
+	Details    map[string]any
+	CreatedAt  time.Time
+}
+
+type AdminAuditRepository interface {
+	Save(ctx context.Context, entry AdminAuditEntry) (int64, error)
+	Finish(
+		ctx context.Context,
+		id int64,
+		statusCode int,
+		details map[string]any,
+	) error
+}
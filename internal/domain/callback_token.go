@@ -9,17 +9,17 @@ import (
 type CallbackToken struct {
 	Token     string
 	Action    string
+	Scope     *string
 	Payload   json.RawMessage
 	CreatedAt time.Time
+	ExpiresAt *time.Time
 }
 
 type CallbackTokenRepository interface {
 	Create(ctx context.Context, callback *CallbackToken) error
+	Get(ctx context.Context, callback *CallbackToken) error
 	Consume(ctx context.Context, callback *CallbackToken) error
 	Delete(ctx context.Context, token string, action string) error
-	DeleteByActionAndOrderID(
-		ctx context.Context,
-		action string,
-		orderID int,
-	) error
+	DeleteByScope(ctx context.Context, scope string) error
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
 }
@@ -0,0 +1,27 @@
+package domain
+
+import "context"
+
+// ExpertRating aggregates the ratings of an expert's orders. The recent
+// figures cover the last 30 days.
+type ExpertRating struct {
+	ExpertID      int
+	Count         int
+	Average       float64
+	RecentCount   int
+	RecentAverage float64
+}
+
+// Trend is how far the recent average is above the overall one; zero when
+// there are no recent ratings.
+func (r ExpertRating) Trend() float64 {
+	if r.RecentCount == 0 {
+		return 0
+	}
+	return r.RecentAverage - r.Average
+}
+
+type ExpertRatingRepository interface {
+	Refresh(ctx context.Context) error
+	GetAll(ctx context.Context) ([]ExpertRating, error)
+}
@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type FraudHit struct {
+	ID         int64
+	OrderID    int
+	SenderRole SenderRole
+	ChatID     int64
+	MessageID  int
+	Rule       string
+	Action     string
+	CreatedAt  time.Time
+}
+
+type FraudHitRepository interface {
+	Save(ctx context.Context, hit FraudHit) error
+	GetByOrder(ctx context.Context, orderID int) ([]FraudHit, error)
+}
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"slices"
 	"time"
 )
 
@@ -16,6 +17,18 @@ const (
 	OrderDeclined        OrderStatus = "declined"
 )
 
+// supportTransitions lists the status changes support may force on an
+// order outside the normal Telegram flow.
+var supportTransitions = map[OrderStatus][]OrderStatus{
+	OrderNew:             {OrderCanceled},
+	OrderAccepted:        {OrderCanceled},
+	OrderExpertConfirmed: {OrderCanceled},
+}
+
+func CanSupportTransition(from, to OrderStatus) bool {
+	return slices.Contains(supportTransitions[from], to)
+}
+
 type Order struct {
 	ID         int
 	Token      string
@@ -32,6 +45,7 @@ type Order struct {
 
 	CreatedAt *time.Time
 	UpdatedAt *time.Time
+	PausedAt  *time.Time
 
 	UserChatID int64
 	TopicID    *int64
@@ -55,9 +69,22 @@ type ChatMessage struct {
 	MessageType MessageType
 	Text        *string
 	Media       map[string]any
+	IsRedacted  bool
 	CreatedAt   time.Time
 }
 
+// OrderFilter narrows an order listing; zero fields don't filter. To is
+// exclusive.
+type OrderFilter struct {
+	Status   OrderStatus
+	GameID   int
+	ExpertID int
+	From     *time.Time
+	To       *time.Time
+	Limit    int
+	Offset   int
+}
+
 type OrderRepository interface {
 	Create(ctx context.Context, order Order) (int, error)
 	UpdateStatus(ctx context.Context, order Order, status OrderStatus) error
@@ -66,4 +93,14 @@ type OrderRepository interface {
 	FindByField(ctx context.Context, where string, arg any) (*OrderFull, error)
 	FindByToken(ctx context.Context, token string) (*OrderFull, error)
 	FindByID(ctx context.Context, id int) (*OrderFull, error)
+	PauseActiveByUser(ctx context.Context, chatID int64) ([]Order, error)
+	ResumeActiveByUser(ctx context.Context, chatID int64) ([]Order, error)
+	CancelPausedBefore(ctx context.Context, before time.Time) ([]Order, error)
+	ClaimReviewReminders(
+		ctx context.Context,
+		before time.Time,
+		limit int,
+	) ([]Order, error)
+	CountActiveByExpert(ctx context.Context) (map[int]int, error)
+	List(ctx context.Context, filter OrderFilter) ([]Order, int, error)
 }
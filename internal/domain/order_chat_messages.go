@@ -33,9 +33,10 @@ type OrderChatMessages struct {
 	MessageType    MessageType
 	Text           *string
 	Media          map[string]any
+	IsRedacted     bool
 	CreatedAt      time.Time
 }
 
 type OrderChatMessagesRepository interface {
-	Save(ctx context.Context, msg *OrderChatMessages) error
+	Save(ctx context.Context, msg *OrderChatMessages, relay *OutboxJob) error
 }
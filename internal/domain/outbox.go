@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type OutboxStatus string
+
+const (
+	OutboxPending OutboxStatus = "pending"
+	OutboxSent    OutboxStatus = "sent"
+	OutboxFailed  OutboxStatus = "failed"
+)
+
+type OutboxJob struct {
+	ID             int64
+	IdempotencyKey string
+	OrderID        *int
+	ChatID         int64
+	Method         string
+	Params         map[string]string
+	Status         OutboxStatus
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastError      *string
+	CreatedAt      time.Time
+	SentAt         *time.Time
+}
+
+type OutboxRepository interface {
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]OutboxJob, error)
+	MarkSent(ctx context.Context, id int64) error
+	MarkRetry(ctx context.Context, id int64, nextAttemptAt time.Time, lastErr string) error
+	MarkFailed(ctx context.Context, id int64, lastErr string) error
+	GetStuck(ctx context.Context, createdBefore time.Time, limit int) ([]OutboxJob, error)
+	DeleteSentBefore(ctx context.Context, before time.Time) (int64, error)
+	Release(ctx context.Context, ids []int64) error
+}
@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type ResponseTemplate struct {
+	ID        int
+	ExpertID  *int
+	Title     string
+	Body      string
+	CreatedAt time.Time
+}
+
+type ResponseTemplateRepository interface {
+	GetAvailable(ctx context.Context, expertID int) ([]ResponseTemplate, error)
+	GetByID(ctx context.Context, id int) (*ResponseTemplate, error)
+}
@@ -22,10 +22,62 @@ type Review struct {
 	Status      ReviewStatus
 	CreatedAt   *time.Time
 	PublishedAt *time.Time
+
+	RejectReason *string
+	ModeratedAt  *time.Time
+	ModeratedBy  *int64
+
+	IsAnonymous      bool
+	ChannelChatID    *int64
+	ChannelMessageID *int
+}
+
+// PublishedReview is a published review with the order details shown next
+// to it. AuthorName and AuthorPhotoURL are empty for anonymous reviews.
+type PublishedReview struct {
+	Review
+	GameName       string
+	GameTypeName   string
+	AuthorName     string
+	AuthorPhotoURL string
+}
+
+type ReviewStats struct {
+	Count    int
+	Average  float64
+	ByRating [5]int
 }
 
 type ReviewRepository interface {
 	Create(ctx context.Context, review Review) error
 	Set(ctx context.Context, review Review, status ReviewStatus) error
 	Publish(ctx context.Context, reviewID int) error
+	GetByID(ctx context.Context, reviewID int) (*Review, error)
+	GetPendingModeration(ctx context.Context, limit int) ([]Review, error)
+	Approve(ctx context.Context, reviewID int, moderatorID int64) error
+	Reject(
+		ctx context.Context,
+		reviewID int,
+		moderatorID int64,
+		reason string,
+	) error
+	SetAnonymous(ctx context.Context, orderID int) (*Review, error)
+	SetChannelPost(
+		ctx context.Context,
+		reviewID int,
+		chatID int64,
+		messageID int,
+	) error
+	ListPublished(
+		ctx context.Context,
+		gameID int,
+		limit int,
+		offset int,
+	) ([]PublishedReview, error)
+	GetPublishedStats(ctx context.Context, gameID int) (ReviewStats, error)
+	PublishRatedBefore(
+		ctx context.Context,
+		before time.Time,
+		limit int,
+	) ([]Review, error)
 }
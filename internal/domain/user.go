@@ -11,6 +11,7 @@ type User struct {
 	Name        string
 	PhotoURL    string
 	IsVerified  bool
+	IsReachable bool
 	CreatedAt   time.Time
 	TotalOrders int
 }
@@ -19,6 +20,7 @@ type UserRepository interface {
 	Add(ctx context.Context, user User) error
 	UpdatePhoto(ctx context.Context, user User) error
 	UpdateVerified(ctx context.Context, chatID int64, isVerified bool) error
+	UpdateReachable(ctx context.Context, chatID int64, isReachable bool) error
 	Get(ctx context.Context, user User) (*User, error)
 	IncrementOrders(ctx context.Context, chatID int64) error
 }
@@ -0,0 +1,191 @@
+package fraud
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/m4xvel/monetych_bot/internal/redact"
+)
+
+type Action string
+
+// ActionAllow only records the hit, so it shows up in /search without
+// anyone being notified. It is meant for trying out a new rule from
+// FRAUD_RULES_FILE before giving it a stronger action.
+const (
+	ActionAllow Action = "allow"
+	ActionWarn  Action = "warn"
+	ActionBlock Action = "block"
+	ActionAlert Action = "alert"
+)
+
+// Rule matches when Pattern is found and, if set, Requires is found too.
+// Valid, if set, rejects Pattern matches that only look like a hit.
+type Rule struct {
+	Name     string
+	Pattern  *regexp.Regexp
+	Valid    func(match string) bool
+	Requires *regexp.Regexp
+	Action   Action
+}
+
+type Hit struct {
+	Rule   string
+	Action Action
+	Match  string
+}
+
+type Engine struct {
+	enabled bool
+	rules   []Rule
+}
+
+type ruleConfig struct {
+	Name     string `json:"name"`
+	Pattern  string `json:"pattern"`
+	Requires string `json:"requires"`
+	Action   Action `json:"action"`
+}
+
+// offPlatformPattern catches wording about settling a deal outside the bot.
+// Payment words are routine inside an order and only matter next to it.
+var offPlatformPattern = regexp.MustCompile(`(?i)напрямую|в обход|вне бота|минуя|без бота|в личк|в лс|лично мне|напиши мне`)
+
+func DefaultRules() []Rule {
+	return []Rule{
+		{
+			Name:    "telegram_username",
+			Pattern: regexp.MustCompile(`(?i)(?:^|[^\w@/])@[a-z][a-z0-9_]{3,31}\b`),
+			Action:  ActionBlock,
+		},
+		{
+			Name:    "telegram_link",
+			Pattern: regexp.MustCompile(`(?i)\b(?:t|telegram)\.me/\S+|tg://\S+`),
+			Action:  ActionBlock,
+		},
+		{
+			Name:    "phone_number",
+			Pattern: redact.PhoneMatcher.Pattern,
+			Valid:   redact.PhoneMatcher.Valid,
+			Action:  ActionWarn,
+		},
+		{
+			Name:    "card_number",
+			Pattern: redact.CardMatcher.Pattern,
+			Valid:   redact.CardMatcher.Valid,
+			Action:  ActionAlert,
+		},
+		{
+			Name:     "payment_requisites",
+			Pattern:  regexp.MustCompile(`(?i)реквизит|по номеру карты|на карту|qiwi|юмани|yoomoney`),
+			Requires: offPlatformPattern,
+			Action:   ActionAlert,
+		},
+		{
+			Name:    "external_messenger",
+			Pattern: regexp.MustCompile(`(?i)whats\s?app|вотсап|ватсап|viber|вайбер|discord|дискорд`),
+			Action:  ActionWarn,
+		},
+	}
+}
+
+func New(enabled bool, rules []Rule) *Engine {
+	return &Engine{
+		enabled: enabled,
+		rules:   rules,
+	}
+}
+
+func LoadRules(path string) ([]Rule, error) {
+	if path == "" {
+		return DefaultRules(), nil
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fraud rules file: %w", err)
+	}
+
+	var configs []ruleConfig
+	if err := json.Unmarshal(raw, &configs); err != nil {
+		return nil, fmt.Errorf("failed to parse fraud rules file: %w", err)
+	}
+
+	rules := make([]Rule, 0, len(configs))
+	for _, c := range configs {
+		switch c.Action {
+		case ActionAllow, ActionWarn, ActionBlock, ActionAlert:
+		default:
+			return nil, fmt.Errorf("fraud rule %q: unknown action %q", c.Name, c.Action)
+		}
+
+		re, err := regexp.Compile(c.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("fraud rule %q: invalid pattern: %w", c.Name, err)
+		}
+
+		rule := Rule{
+			Name:    c.Name,
+			Pattern: re,
+			Action:  c.Action,
+		}
+
+		if c.Requires != "" {
+			rule.Requires, err = regexp.Compile(c.Requires)
+			if err != nil {
+				return nil, fmt.Errorf("fraud rule %q: invalid requires pattern: %w", c.Name, err)
+			}
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+func (e *Engine) Check(text string) []Hit {
+	if e == nil || !e.enabled || text == "" {
+		return nil
+	}
+
+	var hits []Hit
+	for _, r := range e.rules {
+		match := r.find(text)
+		if match == "" {
+			continue
+		}
+		if r.Requires != nil && !r.Requires.MatchString(text) {
+			continue
+		}
+		hits = append(hits, Hit{
+			Rule:   r.Name,
+			Action: r.Action,
+			Match:  match,
+		})
+	}
+
+	return hits
+}
+
+func (r Rule) find(text string) string {
+	if r.Valid == nil {
+		return r.Pattern.FindString(text)
+	}
+	for _, match := range r.Pattern.FindAllString(text, -1) {
+		if r.Valid(match) {
+			return match
+		}
+	}
+	return ""
+}
+
+func HasAction(hits []Hit, action Action) bool {
+	for _, h := range hits {
+		if h.Action == action {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,357 @@
+package metrics
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/m4xvel/monetych_bot/internal/logger"
+)
+
+// DefaultBuckets suit handler latencies: most updates finish well under a
+// second, slow ones wait on Telegram or the database.
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+type collector interface {
+	write(ctx context.Context, w *bufio.Writer)
+}
+
+// Registry renders its metrics in the Prometheus text exposition format.
+type Registry struct {
+	mu         sync.Mutex
+	collectors []collector
+}
+
+var Default = &Registry{}
+
+func (r *Registry) register(c collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.collectors = append(r.collectors, c)
+}
+
+func (r *Registry) WriteTo(ctx context.Context, w io.Writer) error {
+	r.mu.Lock()
+	collectors := append([]collector(nil), r.collectors...)
+	r.mu.Unlock()
+
+	bw := bufio.NewWriter(w)
+	for _, c := range collectors {
+		c.write(ctx, bw)
+	}
+	return bw.Flush()
+}
+
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx, cancel := context.WithTimeout(req.Context(), 5*time.Second)
+		defer cancel()
+
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_ = r.WriteTo(ctx, w)
+	})
+}
+
+type series struct {
+	labels []string
+	value  float64
+}
+
+type vec struct {
+	name   string
+	help   string
+	labels []string
+
+	mu     sync.Mutex
+	series map[string]*series
+}
+
+func newVec(name, help string, labels []string) vec {
+	return vec{
+		name:   name,
+		help:   help,
+		labels: labels,
+		series: make(map[string]*series),
+	}
+}
+
+func (v *vec) get(values []string) *series {
+	if !labelsMatch(v.name, v.labels, values) {
+		return nil
+	}
+
+	key := strings.Join(values, "\xff")
+	s, ok := v.series[key]
+	if !ok {
+		s = &series{labels: append([]string(nil), values...)}
+		v.series[key] = s
+	}
+	return s
+}
+
+func (v *vec) sorted() []series {
+	out := make([]series, 0, len(v.series))
+	for _, s := range v.series {
+		out = append(out, *s)
+	}
+	sortSeries(out)
+	return out
+}
+
+type CounterVec struct {
+	vec
+}
+
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	c := &CounterVec{vec: newVec(name, help, labels)}
+	Default.register(c)
+	return c
+}
+
+func (c *CounterVec) Inc(values ...string) {
+	c.Add(1, values...)
+}
+
+func (c *CounterVec) Add(delta float64, values ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if s := c.get(values); s != nil {
+		s.value += delta
+	}
+}
+
+func (c *CounterVec) write(_ context.Context, w *bufio.Writer) {
+	c.mu.Lock()
+	all := c.sorted()
+	c.mu.Unlock()
+
+	writeHeader(w, c.name, c.help, "counter")
+	for _, s := range all {
+		writeSample(w, c.name, c.labels, s.labels, "", "", s.value)
+	}
+}
+
+type Sample struct {
+	Labels []string
+	Value  float64
+}
+
+// GaugeFunc is evaluated on every scrape, so it always reports the current
+// value instead of one pushed by the code being measured.
+type GaugeFunc struct {
+	name    string
+	help    string
+	labels  []string
+	collect func(ctx context.Context) ([]Sample, error)
+}
+
+func NewGaugeFunc(name, help string, fn func() float64) *GaugeFunc {
+	return NewGaugeVecFunc(name, help, nil, func(context.Context) ([]Sample, error) {
+		return []Sample{{Value: fn()}}, nil
+	})
+}
+
+func NewGaugeVecFunc(
+	name, help string,
+	labels []string,
+	fn func(ctx context.Context) ([]Sample, error),
+) *GaugeFunc {
+	g := &GaugeFunc{name: name, help: help, labels: labels, collect: fn}
+	Default.register(g)
+	return g
+}
+
+func (g *GaugeFunc) write(ctx context.Context, w *bufio.Writer) {
+	samples, err := g.collect(ctx)
+	if err != nil {
+		// Leaving the metric out makes the gap visible in dashboards
+		// instead of reporting stale or zero values.
+		return
+	}
+
+	all := make([]series, 0, len(samples))
+	for _, s := range samples {
+		if !labelsMatch(g.name, g.labels, s.Labels) {
+			continue
+		}
+		all = append(all, series{labels: s.Labels, value: s.Value})
+	}
+	sortSeries(all)
+
+	writeHeader(w, g.name, g.help, "gauge")
+	for _, s := range all {
+		writeSample(w, g.name, g.labels, s.labels, "", "", s.value)
+	}
+}
+
+type histogram struct {
+	labels []string
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+type HistogramVec struct {
+	name    string
+	help    string
+	labels  []string
+	buckets []float64
+
+	mu     sync.Mutex
+	series map[string]*histogram
+}
+
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	h := &HistogramVec{
+		name:    name,
+		help:    help,
+		labels:  labels,
+		buckets: buckets,
+		series:  make(map[string]*histogram),
+	}
+	Default.register(h)
+	return h
+}
+
+func (h *HistogramVec) Observe(value float64, values ...string) {
+	if !labelsMatch(h.name, h.labels, values) {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	key := strings.Join(values, "\xff")
+	s, ok := h.series[key]
+	if !ok {
+		s = &histogram{
+			labels: append([]string(nil), values...),
+			counts: make([]uint64, len(h.buckets)),
+		}
+		h.series[key] = s
+	}
+
+	for i, upper := range h.buckets {
+		if value <= upper {
+			s.counts[i]++
+		}
+	}
+	s.sum += value
+	s.count++
+}
+
+func (h *HistogramVec) ObserveSince(start time.Time, values ...string) {
+	h.Observe(time.Since(start).Seconds(), values...)
+}
+
+func (h *HistogramVec) write(_ context.Context, w *bufio.Writer) {
+	h.mu.Lock()
+	all := make([]histogram, 0, len(h.series))
+	for _, s := range h.series {
+		cp := *s
+		cp.counts = append([]uint64(nil), s.counts...)
+		all = append(all, cp)
+	}
+	h.mu.Unlock()
+
+	sort.Slice(all, func(i, j int) bool {
+		return strings.Join(all[i].labels, "\xff") < strings.Join(all[j].labels, "\xff")
+	})
+
+	writeHeader(w, h.name, h.help, "histogram")
+	for _, s := range all {
+		for i, upper := range h.buckets {
+			writeSample(w, h.name+"_bucket", h.labels, s.labels,
+				"le", formatFloat(upper), float64(s.counts[i]))
+		}
+		writeSample(w, h.name+"_bucket", h.labels, s.labels, "le", "+Inf", float64(s.count))
+		writeSample(w, h.name+"_sum", h.labels, s.labels, "", "", s.sum)
+		writeSample(w, h.name+"_count", h.labels, s.labels, "", "", float64(s.count))
+	}
+}
+
+// labelsMatch reports a label count mismatch instead of panicking: a
+// miscounted call site loses its samples, not the update being handled.
+func labelsMatch(name string, labels, values []string) bool {
+	if len(values) == len(labels) {
+		return true
+	}
+	logger.Log.Errorw("metrics: label values don't match the metric, sample dropped",
+		"metric", name,
+		"want", len(labels),
+		"got", len(values),
+	)
+	return false
+}
+
+func sortSeries(all []series) {
+	sort.Slice(all, func(i, j int) bool {
+		return strings.Join(all[i].labels, "\xff") < strings.Join(all[j].labels, "\xff")
+	})
+}
+
+func writeHeader(w *bufio.Writer, name, help, kind string) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, strings.ReplaceAll(help, "\n", " "))
+	fmt.Fprintf(w, "# TYPE %s %s\n", name, kind)
+}
+
+func writeSample(
+	w *bufio.Writer,
+	name string,
+	labels, values []string,
+	extraLabel, extraValue string,
+	value float64,
+) {
+	w.WriteString(name)
+
+	if len(labels) > 0 || extraLabel != "" {
+		w.WriteByte('{')
+		for i, label := range labels {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			writeLabel(w, label, values[i])
+		}
+		if extraLabel != "" {
+			if len(labels) > 0 {
+				w.WriteByte(',')
+			}
+			writeLabel(w, extraLabel, extraValue)
+		}
+		w.WriteByte('}')
+	}
+
+	w.WriteByte(' ')
+	w.WriteString(formatFloat(value))
+	w.WriteByte('\n')
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func writeLabel(w *bufio.Writer, name, value string) {
+	w.WriteString(name)
+	w.WriteString(`="`)
+	labelEscaper.WriteString(w, value)
+	w.WriteByte('"')
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,148 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/m4xvel/monetych_bot/internal/logger"
+	"go.uber.org/zap"
+)
+
+func render(t *testing.T, collectors ...collector) string {
+	t.Helper()
+
+	r := &Registry{}
+	for _, c := range collectors {
+		r.register(c)
+	}
+
+	var buf bytes.Buffer
+	if err := r.WriteTo(context.Background(), &buf); err != nil {
+		t.Fatalf("WriteTo: %v", err)
+	}
+	return buf.String()
+}
+
+func TestExposition(t *testing.T) {
+	logger.Log = zap.NewNop().Sugar()
+
+	tests := []struct {
+		name string
+		make func() collector
+		want string
+	}{
+		{
+			name: "counter sorted by labels",
+			make: func() collector {
+				c := NewCounterVec("test_total", "Test counter.", "op", "kind")
+				c.Inc("save", "internal")
+				c.Add(2, "get", "unavailable")
+				c.Inc("save", "internal")
+				return c
+			},
+			want: `# HELP test_total Test counter.
+# TYPE test_total counter
+test_total{op="get",kind="unavailable"} 2
+test_total{op="save",kind="internal"} 2
+`,
+		},
+		{
+			name: "label values escaped",
+			make: func() collector {
+				c := NewCounterVec("test_escape_total", "Help with\nnewline.", "value")
+				c.Inc(`back\slash "quoted"` + "\nline")
+				return c
+			},
+			want: `# HELP test_escape_total Help with newline.
+# TYPE test_escape_total counter
+test_escape_total{value="back\\slash \"quoted\"\nline"} 1
+`,
+		},
+		{
+			name: "label count mismatch dropped",
+			make: func() collector {
+				c := NewCounterVec("test_mismatch_total", "Mismatch.", "a", "b")
+				c.Inc("only-one")
+				c.Inc("x", "y")
+				return c
+			},
+			want: `# HELP test_mismatch_total Mismatch.
+# TYPE test_mismatch_total counter
+test_mismatch_total{a="x",b="y"} 1
+`,
+		},
+		{
+			name: "histogram buckets",
+			make: func() collector {
+				h := NewHistogramVec("test_seconds", "Test histogram.", []float64{0.1, 1}, "route")
+				h.Observe(0.05, "b")
+				h.Observe(0.5, "b")
+				h.Observe(3, "b")
+				h.Observe(1, "a")
+				h.Observe(2, "a", "extra")
+				return h
+			},
+			want: `# HELP test_seconds Test histogram.
+# TYPE test_seconds histogram
+test_seconds_bucket{route="a",le="0.1"} 0
+test_seconds_bucket{route="a",le="1"} 1
+test_seconds_bucket{route="a",le="+Inf"} 1
+test_seconds_sum{route="a"} 1
+test_seconds_count{route="a"} 1
+test_seconds_bucket{route="b",le="0.1"} 1
+test_seconds_bucket{route="b",le="1"} 2
+test_seconds_bucket{route="b",le="+Inf"} 3
+test_seconds_sum{route="b"} 3.55
+test_seconds_count{route="b"} 3
+`,
+		},
+		{
+			name: "gauge without labels",
+			make: func() collector {
+				return NewGaugeFunc("test_depth", "Test gauge.", func() float64 { return 42 })
+			},
+			want: `# HELP test_depth Test gauge.
+# TYPE test_depth gauge
+test_depth 42
+`,
+		},
+		{
+			name: "gauge vec sorted, bad samples dropped",
+			make: func() collector {
+				return NewGaugeVecFunc("test_load", "Test gauge vec.", []string{"expert_id"},
+					func(context.Context) ([]Sample, error) {
+						return []Sample{
+							{Labels: []string{"2"}, Value: 1},
+							{Labels: []string{"1"}, Value: 0.5},
+							{Labels: nil, Value: 7},
+						}, nil
+					})
+			},
+			want: `# HELP test_load Test gauge vec.
+# TYPE test_load gauge
+test_load{expert_id="1"} 0.5
+test_load{expert_id="2"} 1
+`,
+		},
+		{
+			name: "failing gauge left out",
+			make: func() collector {
+				return NewGaugeVecFunc("test_broken", "Broken.", []string{"x"},
+					func(context.Context) ([]Sample, error) {
+						return nil, errors.New("boom")
+					})
+			},
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := render(t, tt.make()); got != tt.want {
+				t.Fatalf("exposition mismatch\ngot:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
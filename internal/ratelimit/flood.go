@@ -0,0 +1,141 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+type Role string
+
+const (
+	RoleUser   Role = "user"
+	RoleExpert Role = "expert"
+)
+
+type Limit struct {
+	PerMinute int
+	Burst     int
+}
+
+type Config struct {
+	Limits       map[Role]Limit
+	MuteAfter    int
+	MuteFor      time.Duration
+	WarnInterval time.Duration
+}
+
+type Verdict int
+
+const (
+	Allowed Verdict = iota
+	Throttled
+	Muted
+)
+
+type Result struct {
+	Verdict    Verdict
+	Warn       bool
+	JustMuted  bool
+	MutedUntil time.Time
+}
+
+type bucket struct {
+	tokens     float64
+	last       time.Time
+	strikes    int
+	lastStrike time.Time
+	lastWarn   time.Time
+	mutedUntil time.Time
+}
+
+type FloodGuard struct {
+	cfg Config
+
+	mu        sync.Mutex
+	buckets   map[int64]*bucket
+	lastPrune time.Time
+}
+
+const pruneInterval = 10 * time.Minute
+
+func NewFloodGuard(cfg Config) *FloodGuard {
+	return &FloodGuard{
+		cfg:     cfg,
+		buckets: make(map[int64]*bucket),
+	}
+}
+
+// Check spends a token from the sender's bucket.
+func (g *FloodGuard) Check(senderID int64, role Role, now time.Time) Result {
+	limit, ok := g.cfg.Limits[role]
+	if !ok || limit.PerMinute <= 0 || limit.Burst <= 0 {
+		return Result{Verdict: Allowed}
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.pruneLocked(now)
+
+	b, ok := g.buckets[senderID]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), last: now}
+		g.buckets[senderID] = b
+	}
+
+	if now.Before(b.mutedUntil) {
+		return Result{Verdict: Muted, MutedUntil: b.mutedUntil}
+	}
+
+	rate := float64(limit.PerMinute) / time.Minute.Seconds()
+	b.tokens += now.Sub(b.last).Seconds() * rate
+	if b.tokens > float64(limit.Burst) {
+		b.tokens = float64(limit.Burst)
+	}
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return Result{Verdict: Allowed}
+	}
+
+	if now.Sub(b.lastStrike) > g.cfg.MuteFor {
+		b.strikes = 0
+	}
+	b.strikes++
+	b.lastStrike = now
+
+	if g.cfg.MuteAfter > 0 && b.strikes >= g.cfg.MuteAfter {
+		b.strikes = 0
+		b.mutedUntil = now.Add(g.cfg.MuteFor)
+		return Result{
+			Verdict:    Muted,
+			JustMuted:  true,
+			MutedUntil: b.mutedUntil,
+		}
+	}
+
+	res := Result{Verdict: Throttled}
+	if now.Sub(b.lastWarn) >= g.cfg.WarnInterval {
+		b.lastWarn = now
+		res.Warn = true
+	}
+
+	return res
+}
+
+func (g *FloodGuard) pruneLocked(now time.Time) {
+	if now.Sub(g.lastPrune) < pruneInterval {
+		return
+	}
+	g.lastPrune = now
+
+	for senderID, b := range g.buckets {
+		if now.Before(b.mutedUntil) {
+			continue
+		}
+		if now.Sub(b.last) > pruneInterval {
+			delete(g.buckets, senderID)
+		}
+	}
+}
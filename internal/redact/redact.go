@@ -0,0 +1,176 @@
+package redact
+
+import (
+	"regexp"
+	"strings"
+)
+
+type Kind string
+
+const (
+	KindCard  Kind = "card"
+	KindPhone Kind = "phone"
+	KindEmail Kind = "email"
+)
+
+type Config struct {
+	Enabled bool
+	Kinds   []Kind
+}
+
+type Result struct {
+	Text     string
+	Redacted bool
+	Kinds    []Kind
+}
+
+type rule struct {
+	kind Kind
+	re   *regexp.Regexp
+	mask func(match string) (string, bool)
+}
+
+type Pipeline struct {
+	enabled bool
+	rules   []rule
+}
+
+var (
+	cardRe  = regexp.MustCompile(`\b\d(?:[ \-]?\d){12,18}\b`)
+	emailRe = regexp.MustCompile(`(?i)\b[a-z0-9._%+\-]+@[a-z0-9.\-]+\.[a-z]{2,}\b`)
+	// Only phone shapes: an international number after "+", or a Russian
+	// one with the 8/7 trunk prefix. Bare digit runs such as dates, times
+	// and grouped amounts are left alone.
+	phoneRe = regexp.MustCompile(
+		`\+\d{1,3}[ \-]?\(?\d{1,4}\)?(?:[ \-]?\d{2,4}){2,3}\b` +
+			`|\b[78][ \-]?\(?\d{3}\)?[ \-]?\d{3}[ \-]?\d{2}[ \-]?\d{2}\b`,
+	)
+)
+
+// Matcher finds one kind of personal data. Pattern only proposes
+// candidates; Valid drops the ones that merely look like it.
+type Matcher struct {
+	Pattern *regexp.Regexp
+	Valid   func(match string) bool
+}
+
+var (
+	CardMatcher  = Matcher{Pattern: cardRe, Valid: validCard}
+	PhoneMatcher = Matcher{Pattern: phoneRe, Valid: validPhone}
+)
+
+func New(cfg Config) *Pipeline {
+	p := &Pipeline{enabled: cfg.Enabled}
+
+	enabled := make(map[Kind]bool, len(cfg.Kinds))
+	for _, k := range cfg.Kinds {
+		enabled[k] = true
+	}
+
+	// order matters: cards are matched before phones so that a valid card
+	// number is reported as a card and not as a long phone number.
+	if enabled[KindCard] {
+		p.rules = append(p.rules, rule{kind: KindCard, re: cardRe, mask: maskCard})
+	}
+	if enabled[KindEmail] {
+		p.rules = append(p.rules, rule{kind: KindEmail, re: emailRe, mask: maskEmail})
+	}
+	if enabled[KindPhone] {
+		p.rules = append(p.rules, rule{kind: KindPhone, re: phoneRe, mask: maskPhone})
+	}
+
+	return p
+}
+
+func (p *Pipeline) Apply(text string) Result {
+	res := Result{Text: text}
+	if p == nil || !p.enabled || text == "" {
+		return res
+	}
+
+	for _, r := range p.rules {
+		hit := false
+		res.Text = r.re.ReplaceAllStringFunc(res.Text, func(match string) string {
+			masked, ok := r.mask(match)
+			if !ok {
+				return match
+			}
+			hit = true
+			return masked
+		})
+		if hit {
+			res.Redacted = true
+			res.Kinds = append(res.Kinds, r.kind)
+		}
+	}
+
+	return res
+}
+
+func ParseKinds(values []string) []Kind {
+	out := make([]Kind, 0, len(values))
+	for _, v := range values {
+		switch k := Kind(strings.ToLower(strings.TrimSpace(v))); k {
+		case KindCard, KindPhone, KindEmail:
+			out = append(out, k)
+		}
+	}
+	return out
+}
+
+func maskCard(match string) (string, bool) {
+	if !validCard(match) {
+		return "", false
+	}
+	digits := onlyDigits(match)
+	return "[карта •••• " + digits[len(digits)-4:] + "]", true
+}
+
+func maskPhone(match string) (string, bool) {
+	if !validPhone(match) {
+		return "", false
+	}
+	return "[телефон скрыт]", true
+}
+
+func validCard(match string) bool {
+	digits := onlyDigits(match)
+	return len(digits) >= 13 && len(digits) <= 19 && luhnValid(digits)
+}
+
+func validPhone(match string) bool {
+	digits := onlyDigits(match)
+	return len(digits) >= 10 && len(digits) <= 15
+}
+
+func maskEmail(string) (string, bool) {
+	return "[email скрыт]", true
+}
+
+func onlyDigits(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func luhnValid(digits string) bool {
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
@@ -0,0 +1,84 @@
+package redact
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestApplyMasksPhones(t *testing.T) {
+	p := New(Config{Enabled: true, Kinds: []Kind{KindPhone}})
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "international", text: "звони +7 999 123-45-67", want: "звони [телефон скрыт]"},
+		{name: "international compact", text: "+79991234567", want: "[телефон скрыт]"},
+		{name: "international brackets", text: "+1 (555) 123-4567 evenings", want: "[телефон скрыт] evenings"},
+		{name: "trunk eight", text: "8 (999) 123-45-67", want: "[телефон скрыт]"},
+		{name: "trunk compact", text: "мой 89991234567", want: "мой [телефон скрыт]"},
+		{name: "trunk seven", text: "7 999 123 45 67", want: "[телефон скрыт]"},
+		{name: "followed by a number", text: "+7 999 123 45 67 10 штук", want: "[телефон скрыт] 10 штук"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := p.Apply(tt.text)
+			if got.Text != tt.want {
+				t.Fatalf("Apply(%q) = %q, want %q", tt.text, got.Text, tt.want)
+			}
+			if !got.Redacted || !slices.Equal(got.Kinds, []Kind{KindPhone}) {
+				t.Fatalf("Apply(%q) kinds = %v, want [phone]", tt.text, got.Kinds)
+			}
+		})
+	}
+}
+
+func TestApplyKeepsTradeText(t *testing.T) {
+	p := New(Config{Enabled: true, Kinds: []Kind{KindCard, KindPhone, KindEmail}})
+
+	tests := []struct {
+		name string
+		text string
+	}{
+		{name: "date and time", text: "передам 2024-01-15 10:30"},
+		{name: "dotted date", text: "до 15.01.2024 12:00"},
+		{name: "grouped amount", text: "продам 1 500 000 000 золота"},
+		{name: "hyphenated amount", text: "цена 1-500-000-000"},
+		{name: "long id", text: "steam id 76561198012345678"},
+		{name: "order number", text: "заказ 1234567890 оплачен"},
+		{name: "short number", text: "+15 к силе"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := p.Apply(tt.text)
+			if got.Redacted || got.Text != tt.text {
+				t.Fatalf("Apply(%q) = %q (kinds %v), want it unchanged", tt.text, got.Text, got.Kinds)
+			}
+		})
+	}
+}
+
+func TestApplyMasksCards(t *testing.T) {
+	p := New(Config{Enabled: true, Kinds: []Kind{KindCard, KindPhone}})
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "spaced", text: "карта 4111 1111 1111 1111", want: "карта [карта •••• 1111]"},
+		{name: "compact", text: "5555555555554444", want: "[карта •••• 4444]"},
+		{name: "fails luhn", text: "4111 1111 1111 1112", want: "4111 1111 1111 1112"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.Apply(tt.text).Text; got != tt.want {
+				t.Fatalf("Apply(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/m4xvel/monetych_bot/internal/domain"
+	"github.com/m4xvel/monetych_bot/internal/logger"
+)
+
+type AdminAuditRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewAdminAuditRepo(pool *pgxpool.Pool) *AdminAuditRepo {
+	return &AdminAuditRepo{pool: pool}
+}
+
+func (r *AdminAuditRepo) Save(ctx context.Context, entry domain.AdminAuditEntry) (int64, error) {
+	const q = `
+		INSERT INTO admin_audit_log (
+			actor,
+			action,
+			order_id,
+			method,
+			path,
+			status_code,
+			remote_ip,
+			details
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`
+
+	details := entry.Details
+	if details == nil {
+		details = map[string]any{}
+	}
+
+	var id int64
+	if err := r.pool.QueryRow(
+		ctx, q,
+		entry.Actor,
+		entry.Action,
+		entry.OrderID,
+		entry.Method,
+		entry.Path,
+		entry.StatusCode,
+		entry.RemoteIP,
+		details,
+	).Scan(&id); err != nil {
+		wrapped := dbErr("admin_audit.save", err)
+		logger.Log.Errorw("failed to save admin audit entry",
+			"actor", entry.Actor,
+			"action", entry.Action,
+			"err", wrapped,
+		)
+		return 0, wrapped
+	}
+
+	return id, nil
+}
+
+func (r *AdminAuditRepo) Finish(
+	ctx context.Context,
+	id int64,
+	statusCode int,
+	details map[string]any,
+) error {
+	const q = `
+		UPDATE admin_audit_log
+		SET
+			status_code = $2,
+			details = $3
+		WHERE id = $1
+	`
+
+	if details == nil {
+		details = map[string]any{}
+	}
+
+	if _, err := r.pool.Exec(ctx, q, id, statusCode, details); err != nil {
+		wrapped := dbErr("admin_audit.finish", err)
+		logger.Log.Errorw("failed to finish admin audit entry",
+			"audit_id", id,
+			"err", wrapped,
+		)
+		return wrapped
+	}
+
+	return nil
+}
@@ -3,7 +3,7 @@ package postgres
 import (
 	"context"
 	"errors"
-	"strconv"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -24,15 +24,17 @@ func (r *CallbackTokenRepo) Create(
 	callback *domain.CallbackToken,
 ) error {
 	const q = `
-		INSERT INTO callback_tokens (token, action, payload)
-		VALUES ($1, $2, $3)
+		INSERT INTO callback_tokens (token, action, scope, payload, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
 	`
 
 	if _, err := r.pool.Exec(
 		ctx, q,
 		callback.Token,
 		callback.Action,
+		callback.Scope,
 		callback.Payload,
+		callback.ExpiresAt,
 	); err != nil {
 		wrapped := dbErr("callback_token.create", err)
 		logger.Log.Errorw("failed to create callback token",
@@ -44,6 +46,28 @@ func (r *CallbackTokenRepo) Create(
 	return nil
 }
 
+func (r *CallbackTokenRepo) Get(
+	ctx context.Context,
+	callback *domain.CallbackToken,
+) error {
+	const q = `
+		SELECT payload, expires_at
+		FROM callback_tokens
+		WHERE token=$1 AND action=$2
+	`
+
+	err := r.pool.QueryRow(
+		ctx, q,
+		callback.Token,
+		callback.Action,
+	).Scan(&callback.Payload, &callback.ExpiresAt)
+	if err != nil {
+		return dbErr("callback_token.get", err)
+	}
+
+	return nil
+}
+
 func (r *CallbackTokenRepo) Consume(
 	ctx context.Context,
 	callback *domain.CallbackToken,
@@ -51,14 +75,14 @@ func (r *CallbackTokenRepo) Consume(
 	const q = `
 		DELETE FROM callback_tokens
 		WHERE token=$1 AND action=$2
-		RETURNING payload
+		RETURNING scope, payload, expires_at
 	`
 
 	err := r.pool.QueryRow(
 		ctx, q,
 		callback.Token,
 		callback.Action,
-	).Scan(&callback.Payload)
+	).Scan(&callback.Scope, &callback.Payload, &callback.ExpiresAt)
 
 	if errors.Is(err, pgx.ErrNoRows) {
 		wrapped := dbErr("callback_token.consume", err)
@@ -96,27 +120,36 @@ func (r *CallbackTokenRepo) Delete(
 	return nil
 }
 
-func (r *CallbackTokenRepo) DeleteByActionAndOrderID(
+func (r *CallbackTokenRepo) DeleteByScope(
 	ctx context.Context,
-	action string,
-	orderID int,
+	scope string,
 ) error {
-
 	const q = `
 		DELETE FROM callback_tokens
-		WHERE action = $1
-		  AND payload->>'order_id' = $2
+		WHERE scope = $1
 	`
 
-	_, err := r.pool.Exec(
-		ctx,
-		q,
-		action,
-		strconv.Itoa(orderID),
-	)
+	if _, err := r.pool.Exec(ctx, q, scope); err != nil {
+		return dbErr("callback_token.delete_by_scope", err)
+	}
+
+	return nil
+}
 
+func (r *CallbackTokenRepo) DeleteExpired(
+	ctx context.Context,
+	before time.Time,
+) (int64, error) {
+	const q = `
+		DELETE FROM callback_tokens
+		WHERE expires_at IS NOT NULL
+		  AND expires_at < $1
+	`
+
+	tag, err := r.pool.Exec(ctx, q, before)
 	if err != nil {
-		return dbErr("callback_token.delete_by_action_and_order_id", err)
+		return 0, dbErr("callback_token.delete_expired", err)
 	}
-	return nil
+
+	return tag.RowsAffected(), nil
 }
@@ -23,6 +23,10 @@ func dbErr(op string, err error) error {
 	if err == nil {
 		return nil
 	}
+	return observeDBError(classifyDBErr(op, err))
+}
+
+func classifyDBErr(op string, err error) *apperr.DBError {
 	if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows) {
 		return &apperr.DBError{Op: op, Kind: apperr.KindNotFound, Err: err}
 	}
@@ -55,9 +59,9 @@ func dbErr(op string, err error) error {
 }
 
 func dbErrKind(op string, kind apperr.Kind, err error) error {
-	return &apperr.DBError{Op: op, Kind: kind, Err: err}
+	return observeDBError(&apperr.DBError{Op: op, Kind: kind, Err: err})
 }
 
 func dbErrCode(op string, kind apperr.Kind, code string, err error) error {
-	return &apperr.DBError{Op: op, Kind: kind, Code: code, Err: err}
+	return observeDBError(&apperr.DBError{Op: op, Kind: kind, Code: code, Err: err})
 }
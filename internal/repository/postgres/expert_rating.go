@@ -0,0 +1,77 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/m4xvel/monetych_bot/internal/domain"
+	"github.com/m4xvel/monetych_bot/internal/logger"
+)
+
+type ExpertRatingRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewExpertRatingRepo(pool *pgxpool.Pool) *ExpertRatingRepo {
+	return &ExpertRatingRepo{pool: pool}
+}
+
+func (r *ExpertRatingRepo) Refresh(ctx context.Context) error {
+	const q = `REFRESH MATERIALIZED VIEW CONCURRENTLY expert_ratings`
+
+	if _, err := r.pool.Exec(ctx, q); err != nil {
+		wrapped := dbErr("expert_rating.refresh", err)
+		logger.Log.Errorw("failed to refresh expert ratings",
+			"err", wrapped,
+		)
+		return wrapped
+	}
+
+	return nil
+}
+
+func (r *ExpertRatingRepo) GetAll(
+	ctx context.Context,
+) ([]domain.ExpertRating, error) {
+	const q = `
+		SELECT
+			expert_id,
+			rating_count,
+			average,
+			recent_count,
+			recent_average
+		FROM expert_ratings
+	`
+
+	rows, err := r.pool.Query(ctx, q)
+	if err != nil {
+		wrapped := dbErr("expert_rating.get_all", err)
+		logger.Log.Errorw("failed to query expert ratings",
+			"err", wrapped,
+		)
+		return nil, wrapped
+	}
+	defer rows.Close()
+
+	var out []domain.ExpertRating
+
+	for rows.Next() {
+		var er domain.ExpertRating
+		if err := rows.Scan(
+			&er.ExpertID,
+			&er.Count,
+			&er.Average,
+			&er.RecentCount,
+			&er.RecentAverage,
+		); err != nil {
+			return nil, dbErr("expert_rating.get_all", err)
+		}
+		out = append(out, er)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, dbErr("expert_rating.get_all", err)
+	}
+
+	return out, nil
+}
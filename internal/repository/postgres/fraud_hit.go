@@ -0,0 +1,116 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/m4xvel/monetych_bot/internal/domain"
+	"github.com/m4xvel/monetych_bot/internal/logger"
+)
+
+type FraudHitRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewFraudHitRepo(pool *pgxpool.Pool) *FraudHitRepo {
+	return &FraudHitRepo{pool: pool}
+}
+
+func (r *FraudHitRepo) Save(ctx context.Context, hit domain.FraudHit) error {
+	const q = `
+		INSERT INTO order_fraud_hits (
+			order_id,
+			sender_role,
+			chat_id,
+			message_id,
+			rule,
+			action
+		)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	if _, err := r.pool.Exec(
+		ctx, q,
+		hit.OrderID,
+		hit.SenderRole,
+		hit.ChatID,
+		hit.MessageID,
+		hit.Rule,
+		hit.Action,
+	); err != nil {
+		wrapped := dbErr("fraud_hit.save", err)
+		logger.Log.Errorw("failed to save fraud hit",
+			"order_id", hit.OrderID,
+			"rule", hit.Rule,
+			"err", wrapped,
+		)
+		return wrapped
+	}
+
+	return nil
+}
+
+func (r *FraudHitRepo) GetByOrder(
+	ctx context.Context,
+	orderID int,
+) ([]domain.FraudHit, error) {
+	const q = `
+		SELECT
+			id,
+			order_id,
+			sender_role,
+			chat_id,
+			message_id,
+			rule,
+			action,
+			created_at
+		FROM order_fraud_hits
+		WHERE order_id = $1
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.pool.Query(ctx, q, orderID)
+	if err != nil {
+		wrapped := dbErr("fraud_hit.get_by_order", err)
+		logger.Log.Errorw("failed to query fraud hits",
+			"order_id", orderID,
+			"err", wrapped,
+		)
+		return nil, wrapped
+	}
+	defer rows.Close()
+
+	var out []domain.FraudHit
+	for rows.Next() {
+		var h domain.FraudHit
+		if err := rows.Scan(
+			&h.ID,
+			&h.OrderID,
+			&h.SenderRole,
+			&h.ChatID,
+			&h.MessageID,
+			&h.Rule,
+			&h.Action,
+			&h.CreatedAt,
+		); err != nil {
+			wrapped := dbErr("fraud_hit.scan", err)
+			logger.Log.Errorw("failed to scan fraud hit row",
+				"order_id", orderID,
+				"err", wrapped,
+			)
+			return nil, wrapped
+		}
+		out = append(out, h)
+	}
+
+	if err := rows.Err(); err != nil {
+		wrapped := dbErr("fraud_hit.rows", err)
+		logger.Log.Errorw("rows error while iterating fraud hits",
+			"order_id", orderID,
+			"err", wrapped,
+		)
+		return nil, wrapped
+	}
+
+	return out, nil
+}
@@ -0,0 +1,42 @@
+package postgres
+
+import (
+	"github.com/m4xvel/monetych_bot/internal/apperr"
+	"github.com/m4xvel/monetych_bot/internal/domain"
+	"github.com/m4xvel/monetych_bot/internal/metrics"
+)
+
+const transitionFromNone = "none"
+
+var (
+	dbErrorsTotal = metrics.NewCounterVec(
+		"bot_db_errors_total",
+		"Repository errors, by operation and apperr kind.",
+		"op", "kind",
+	)
+	orderTransitions = metrics.NewCounterVec(
+		"bot_order_status_transitions_total",
+		"Order status changes written to the database.",
+		"from", "to",
+	)
+	orderPauses = metrics.NewCounterVec(
+		"bot_order_pauses_total",
+		"Unfinished orders paused and resumed because the user blocked or unblocked the bot.",
+		"action",
+	)
+)
+
+// observeDBError counts e unless it is a plain miss: lookups that find
+// nothing are routine and would drown out real failures.
+func observeDBError(e *apperr.DBError) error {
+	if e.Kind != apperr.KindNotFound {
+		dbErrorsTotal.Inc(e.Op, string(e.Kind))
+	}
+	return e
+}
+
+func observeOrderTransition(from string, to domain.OrderStatus, n int) {
+	if n > 0 {
+		orderTransitions.Add(float64(n), from, string(to))
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -64,6 +65,10 @@ func (r *OrderRepo) Create(ctx context.Context, order domain.Order) (int, error)
 		return 0, wrapped
 	}
 
+	if id != 0 {
+		observeOrderTransition(transitionFromNone, domain.OrderNew, 1)
+	}
+
 	return id, nil
 }
 
@@ -96,6 +101,8 @@ func (r *OrderRepo) UpdateStatus(
 		return dbErrCode("order.update_status", apperr.KindConflict, apperr.DBCodeOrderAlreadyProcessed, nil)
 	}
 
+	observeOrderTransition(string(status), order.Status, 1)
+
 	return nil
 }
 
@@ -145,9 +152,12 @@ func (r *OrderRepo) Get(ctx context.Context, orderID int) (*domain.Order, error)
 			substr(o.order_token,1,4) || '-' ||
 			substr(o.order_token,5,4) || '-' ||
 			substr(o.order_token,9,4) AS pretty_token,
+			o.status,
 			o.thread_id,
 			o.game_name_at_purchase,
 			o.game_type_name_at_purchase,
+			o.user_name_at_purchase,
+			o.paused_at,
 			u.chat_id,
 			e.topic_id
 		FROM orders o
@@ -159,9 +169,12 @@ func (r *OrderRepo) Get(ctx context.Context, orderID int) (*domain.Order, error)
 	if err := r.pool.QueryRow(ctx, q, orderID).Scan(
 		&o.ID,
 		&o.Token,
+		&o.Status,
 		&o.ThreadID,
 		&o.GameNameAtPurchase,
 		&o.GameTypeNameAtPurchase,
+		&o.UserNameAtPurchase,
+		&o.PausedAt,
 		&o.UserChatID,
 		&o.TopicID,
 	); err != nil {
@@ -198,9 +211,9 @@ func (r *OrderRepo) FindByField(
 				u.is_verified, 
 				u.created_at, 
 				u.total_orders,
-				e.id, 
-				e.topic_id, 
-				e.is_active,
+				coalesce(e.id, 0),
+				coalesce(e.topic_id, 0),
+				coalesce(e.is_active, false),
 				g.id, g.name,
 				gt.id, gt.name
 			FROM orders o
@@ -283,6 +296,7 @@ func (r *OrderRepo) FindByField(
 			message_type,
 			text_enc,
     	media_enc,
+			is_redacted,
 			created_at
 		FROM order_chat_messages
 		WHERE order_id = $1
@@ -312,6 +326,7 @@ func (r *OrderRepo) FindByField(
 			&msg.MessageType,
 			&textEnc,
 			&mediaEnc,
+			&msg.IsRedacted,
 			&msg.CreatedAt,
 		); err != nil {
 			wrapped := dbErr("order.messages_scan", err)
@@ -353,3 +368,493 @@ func (r *OrderRepo) FindByToken(ctx context.Context, token string) (*domain.Orde
 func (r *OrderRepo) FindByID(ctx context.Context, id int) (*domain.OrderFull, error) {
 	return r.FindByField(ctx, "o.id = $1", id)
 }
+
+// PauseActiveByUser marks every unfinished order of the user as paused and
+// returns them.
+func (r *OrderRepo) PauseActiveByUser(
+	ctx context.Context,
+	chatID int64,
+) ([]domain.Order, error) {
+	const q = `
+		WITH paused AS (
+			UPDATE orders o
+			SET
+				paused_at = now(),
+				updated_at = now()
+			FROM users u
+			WHERE u.id = o.user_id
+				AND u.chat_id = $1
+				AND o.status IN ($2, $3)
+				AND o.paused_at IS NULL
+			RETURNING o.id, o.order_token, o.thread_id, o.expert_id, o.paused_at
+		)
+		SELECT
+			p.id,
+			substr(p.order_token,1,4) || '-' ||
+			substr(p.order_token,5,4) || '-' ||
+			substr(p.order_token,9,4) AS pretty_token,
+			p.thread_id,
+			p.paused_at,
+			e.topic_id
+		FROM paused p
+		LEFT JOIN experts e ON e.id = p.expert_id
+	`
+
+	rows, err := r.pool.Query(
+		ctx, q,
+		chatID,
+		domain.OrderAccepted,
+		domain.OrderExpertConfirmed,
+	)
+	if err != nil {
+		wrapped := dbErr("order.pause_active_by_user", err)
+		logger.Log.Errorw("order repo: pause active orders failed",
+			"user_chat_id", chatID,
+			"err", wrapped,
+		)
+		return nil, wrapped
+	}
+	defer rows.Close()
+
+	var out []domain.Order
+	for rows.Next() {
+		o := domain.Order{UserChatID: chatID}
+		if err := rows.Scan(
+			&o.ID,
+			&o.Token,
+			&o.ThreadID,
+			&o.PausedAt,
+			&o.TopicID,
+		); err != nil {
+			wrapped := dbErr("order.pause_active_by_user_scan", err)
+			logger.Log.Errorw("order repo: failed to scan paused order",
+				"user_chat_id", chatID,
+				"err", wrapped,
+			)
+			return nil, wrapped
+		}
+		out = append(out, o)
+	}
+
+	if err := rows.Err(); err != nil {
+		wrapped := dbErr("order.pause_active_by_user_rows", err)
+		logger.Log.Errorw("order repo: rows error while pausing orders",
+			"user_chat_id", chatID,
+			"err", wrapped,
+		)
+		return nil, wrapped
+	}
+
+	orderPauses.Add(float64(len(out)), "pause")
+
+	return out, nil
+}
+
+// ResumeActiveByUser clears the pause on every unfinished order of the user
+// and returns them.
+func (r *OrderRepo) ResumeActiveByUser(
+	ctx context.Context,
+	chatID int64,
+) ([]domain.Order, error) {
+	const q = `
+		WITH resumed AS (
+			UPDATE orders o
+			SET
+				paused_at = NULL,
+				updated_at = now()
+			FROM users u
+			WHERE u.id = o.user_id
+				AND u.chat_id = $1
+				AND o.status IN ($2, $3)
+				AND o.paused_at IS NOT NULL
+			RETURNING o.id, o.order_token, o.thread_id, o.expert_id
+		)
+		SELECT
+			r.id,
+			substr(r.order_token,1,4) || '-' ||
+			substr(r.order_token,5,4) || '-' ||
+			substr(r.order_token,9,4) AS pretty_token,
+			r.thread_id,
+			e.topic_id
+		FROM resumed r
+		LEFT JOIN experts e ON e.id = r.expert_id
+	`
+
+	rows, err := r.pool.Query(
+		ctx, q,
+		chatID,
+		domain.OrderAccepted,
+		domain.OrderExpertConfirmed,
+	)
+	if err != nil {
+		wrapped := dbErr("order.resume_active_by_user", err)
+		logger.Log.Errorw("order repo: resume active orders failed",
+			"user_chat_id", chatID,
+			"err", wrapped,
+		)
+		return nil, wrapped
+	}
+	defer rows.Close()
+
+	var out []domain.Order
+	for rows.Next() {
+		o := domain.Order{UserChatID: chatID}
+		if err := rows.Scan(
+			&o.ID,
+			&o.Token,
+			&o.ThreadID,
+			&o.TopicID,
+		); err != nil {
+			wrapped := dbErr("order.resume_active_by_user_scan", err)
+			logger.Log.Errorw("order repo: failed to scan resumed order",
+				"user_chat_id", chatID,
+				"err", wrapped,
+			)
+			return nil, wrapped
+		}
+		out = append(out, o)
+	}
+
+	if err := rows.Err(); err != nil {
+		wrapped := dbErr("order.resume_active_by_user_rows", err)
+		logger.Log.Errorw("order repo: rows error while resuming orders",
+			"user_chat_id", chatID,
+			"err", wrapped,
+		)
+		return nil, wrapped
+	}
+
+	orderPauses.Add(float64(len(out)), "resume")
+
+	return out, nil
+}
+
+func (r *OrderRepo) CancelPausedBefore(
+	ctx context.Context,
+	before time.Time,
+) ([]domain.Order, error) {
+	const q = `
+		WITH due AS (
+			SELECT id, status
+			FROM orders
+			WHERE paused_at IS NOT NULL
+				AND paused_at < $1
+				AND status IN ($3, $4)
+			FOR UPDATE
+		),
+		canceled AS (
+			UPDATE orders o
+			SET
+				status = $2,
+				updated_at = now()
+			FROM due d
+			WHERE o.id = d.id
+			RETURNING o.id, o.thread_id, o.user_id, o.expert_id, o.paused_at,
+				d.status AS prev_status
+		)
+		SELECT
+			c.id,
+			c.thread_id,
+			c.paused_at,
+			u.chat_id,
+			e.topic_id,
+			c.prev_status
+		FROM canceled c
+		JOIN users u ON u.id = c.user_id
+		LEFT JOIN experts e ON e.id = c.expert_id
+	`
+
+	rows, err := r.pool.Query(
+		ctx, q,
+		before,
+		domain.OrderCanceled,
+		domain.OrderAccepted,
+		domain.OrderExpertConfirmed,
+	)
+	if err != nil {
+		wrapped := dbErr("order.cancel_paused_before", err)
+		logger.Log.Errorw("order repo: cancel paused orders failed",
+			"before", before,
+			"err", wrapped,
+		)
+		return nil, wrapped
+	}
+	defer rows.Close()
+
+	var out []domain.Order
+	prev := make(map[string]int)
+	for rows.Next() {
+		o := domain.Order{Status: domain.OrderCanceled}
+		var from string
+		if err := rows.Scan(
+			&o.ID,
+			&o.ThreadID,
+			&o.PausedAt,
+			&o.UserChatID,
+			&o.TopicID,
+			&from,
+		); err != nil {
+			wrapped := dbErr("order.cancel_paused_scan", err)
+			logger.Log.Errorw("order repo: failed to scan canceled order",
+				"err", wrapped,
+			)
+			return nil, wrapped
+		}
+		out = append(out, o)
+		prev[from]++
+	}
+
+	if err := rows.Err(); err != nil {
+		wrapped := dbErr("order.cancel_paused_rows", err)
+		logger.Log.Errorw("order repo: rows error while canceling paused orders",
+			"err", wrapped,
+		)
+		return nil, wrapped
+	}
+
+	for from, n := range prev {
+		observeOrderTransition(from, domain.OrderCanceled, n)
+	}
+
+	return out, nil
+}
+
+// ClaimReviewReminders marks up to limit orders completed before the given
+// time as reminded and returns them. Orders that already have a rating and
+// users busy with another order are left out.
+func (r *OrderRepo) ClaimReviewReminders(
+	ctx context.Context,
+	before time.Time,
+	limit int,
+) ([]domain.Order, error) {
+	const q = `
+		WITH due AS (
+			UPDATE orders
+			SET review_reminder_sent_at = now()
+			WHERE id IN (
+				SELECT c.id
+				FROM orders c
+				WHERE c.status = $2
+					AND c.updated_at < $1
+					AND c.review_reminder_sent_at IS NULL
+					AND NOT EXISTS (
+						SELECT 1 FROM reviews rv WHERE rv.order_id = c.id
+					)
+					AND NOT EXISTS (
+						SELECT 1
+						FROM orders a
+						WHERE a.user_id = c.user_id
+							AND a.status IN ($3, $4, $5)
+					)
+				ORDER BY c.updated_at
+				LIMIT $6
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING id, user_id, updated_at
+		)
+		SELECT
+			d.id,
+			d.updated_at,
+			u.chat_id
+		FROM due d
+		JOIN users u ON u.id = d.user_id
+	`
+
+	rows, err := r.pool.Query(
+		ctx, q,
+		before,
+		domain.OrderCompleted,
+		domain.OrderNew,
+		domain.OrderAccepted,
+		domain.OrderExpertConfirmed,
+		limit,
+	)
+	if err != nil {
+		wrapped := dbErr("order.claim_review_reminders", err)
+		logger.Log.Errorw("order repo: claim review reminders failed",
+			"before", before,
+			"err", wrapped,
+		)
+		return nil, wrapped
+	}
+	defer rows.Close()
+
+	var out []domain.Order
+	for rows.Next() {
+		o := domain.Order{Status: domain.OrderCompleted}
+		if err := rows.Scan(
+			&o.ID,
+			&o.UpdatedAt,
+			&o.UserChatID,
+		); err != nil {
+			wrapped := dbErr("order.claim_review_reminders_scan", err)
+			logger.Log.Errorw("order repo: failed to scan reminded order",
+				"err", wrapped,
+			)
+			return nil, wrapped
+		}
+		out = append(out, o)
+	}
+
+	if err := rows.Err(); err != nil {
+		wrapped := dbErr("order.claim_review_reminders_rows", err)
+		logger.Log.Errorw("order repo: rows error while claiming review reminders",
+			"err", wrapped,
+		)
+		return nil, wrapped
+	}
+
+	return out, nil
+}
+
+// CountActiveByExpert returns the number of accepted and confirmed orders
+// per active expert, including experts with none.
+func (r *OrderRepo) CountActiveByExpert(ctx context.Context) (map[int]int, error) {
+	const q = `
+		SELECT e.id, count(o.id)
+		FROM experts e
+		LEFT JOIN orders o
+			ON o.expert_id = e.id
+			AND o.status IN ($1, $2)
+		WHERE e.is_active = true
+		GROUP BY e.id
+	`
+
+	rows, err := r.pool.Query(
+		ctx, q,
+		domain.OrderAccepted,
+		domain.OrderExpertConfirmed,
+	)
+	if err != nil {
+		wrapped := dbErr("order.count_active_by_expert", err)
+		logger.Log.Errorw("order repo: count active orders by expert failed",
+			"err", wrapped,
+		)
+		return nil, wrapped
+	}
+	defer rows.Close()
+
+	out := make(map[int]int)
+	for rows.Next() {
+		var expertID, count int
+		if err := rows.Scan(&expertID, &count); err != nil {
+			wrapped := dbErr("order.count_active_by_expert_scan", err)
+			logger.Log.Errorw("order repo: failed to scan active order count",
+				"err", wrapped,
+			)
+			return nil, wrapped
+		}
+		out[expertID] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		wrapped := dbErr("order.count_active_by_expert_rows", err)
+		logger.Log.Errorw("order repo: rows error while counting active orders",
+			"err", wrapped,
+		)
+		return nil, wrapped
+	}
+
+	return out, nil
+}
+
+// List returns a page of orders matching filter, newest first, together
+// with the total number of matches.
+func (r *OrderRepo) List(
+	ctx context.Context,
+	filter domain.OrderFilter,
+) ([]domain.Order, int, error) {
+	const q = `
+		SELECT
+			o.id,
+			o.order_token,
+			o.status,
+			o.user_id,
+			o.expert_id,
+			o.thread_id,
+			o.game_id,
+			o.game_type_id,
+			o.user_name_at_purchase,
+			o.game_name_at_purchase,
+			o.game_type_name_at_purchase,
+			o.created_at,
+			o.updated_at,
+			o.paused_at,
+			coalesce(u.chat_id, 0),
+			count(*) OVER ()
+		FROM orders o
+		LEFT JOIN users u ON u.id = o.user_id
+		WHERE ($1::text = '' OR o.status::text = $1)
+			AND ($2::int = 0 OR o.game_id = $2)
+			AND ($3::int = 0 OR o.expert_id = $3)
+			AND ($4::timestamptz IS NULL OR o.created_at >= $4)
+			AND ($5::timestamptz IS NULL OR o.created_at < $5)
+		ORDER BY o.created_at DESC, o.id DESC
+		LIMIT $6 OFFSET $7
+	`
+
+	rows, err := r.pool.Query(
+		ctx, q,
+		string(filter.Status),
+		filter.GameID,
+		filter.ExpertID,
+		filter.From,
+		filter.To,
+		filter.Limit,
+		filter.Offset,
+	)
+	if err != nil {
+		wrapped := dbErr("order.list", err)
+		logger.Log.Errorw("order repo: list failed",
+			"status", filter.Status,
+			"game_id", filter.GameID,
+			"expert_id", filter.ExpertID,
+			"err", wrapped,
+		)
+		return nil, 0, wrapped
+	}
+	defer rows.Close()
+
+	var (
+		out   []domain.Order
+		total int
+	)
+	for rows.Next() {
+		var o domain.Order
+		if err := rows.Scan(
+			&o.ID,
+			&o.Token,
+			&o.Status,
+			&o.UserID,
+			&o.ExpertID,
+			&o.ThreadID,
+			&o.GameID,
+			&o.GameTypeID,
+			&o.UserNameAtPurchase,
+			&o.GameNameAtPurchase,
+			&o.GameTypeNameAtPurchase,
+			&o.CreatedAt,
+			&o.UpdatedAt,
+			&o.PausedAt,
+			&o.UserChatID,
+			&total,
+		); err != nil {
+			wrapped := dbErr("order.list_scan", err)
+			logger.Log.Errorw("order repo: failed to scan listed order",
+				"err", wrapped,
+			)
+			return nil, 0, wrapped
+		}
+		out = append(out, o)
+	}
+
+	if err := rows.Err(); err != nil {
+		wrapped := dbErr("order.list_rows", err)
+		logger.Log.Errorw("order repo: rows error while listing orders",
+			"err", wrapped,
+		)
+		return nil, 0, wrapped
+	}
+
+	return out, total, nil
+}
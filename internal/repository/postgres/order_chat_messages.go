@@ -28,6 +28,7 @@ func NewOrderChatMessagesRepo(
 func (r *OrderChatMessagesRepo) Save(
 	ctx context.Context,
 	msg *domain.OrderChatMessages,
+	relay *domain.OutboxJob,
 ) error {
 	var textEnc []byte
 	var mediaEnc []byte
@@ -62,13 +63,22 @@ func (r *OrderChatMessagesRepo) Save(
 			message_id,
 			message_type,
 			text_enc,
-			media_enc
+			media_enc,
+			is_redacted
 		)
-		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)
 		ON CONFLICT DO NOTHING
 	`
 
-	cmd, err := r.pool.Exec(ctx, q,
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		wrapped := dbErr("order_chat_messages.begin", err)
+		logger.Log.Errorw("begin chat message tx failed", "err", wrapped)
+		return wrapped
+	}
+	defer tx.Rollback(ctx)
+
+	cmd, err := tx.Exec(ctx, q,
 		msg.OrderID,
 		msg.SenderRole,
 		msg.SenderUserID,
@@ -78,6 +88,7 @@ func (r *OrderChatMessagesRepo) Save(
 		msg.MessageType,
 		textEnc,
 		mediaEnc,
+		msg.IsRedacted,
 	)
 
 	if err != nil {
@@ -86,8 +97,21 @@ func (r *OrderChatMessagesRepo) Save(
 		return wrapped
 	}
 
-	if cmd.RowsAffected() == 0 {
-		return nil
+	// A duplicate update must not queue the relay a second time.
+	if cmd.RowsAffected() > 0 && relay != nil {
+		if err := insertOutboxJob(ctx, tx, r.crypto, relay); err != nil {
+			logger.Log.Errorw("insert outbox job failed",
+				"order_id", msg.OrderID,
+				"err", err,
+			)
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		wrapped := dbErr("order_chat_messages.commit", err)
+		logger.Log.Errorw("commit chat message tx failed", "err", wrapped)
+		return wrapped
 	}
 
 	return nil
@@ -0,0 +1,358 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/m4xvel/monetych_bot/internal/crypto"
+	"github.com/m4xvel/monetych_bot/internal/domain"
+	"github.com/m4xvel/monetych_bot/internal/logger"
+)
+
+type OutboxRepo struct {
+	pool   *pgxpool.Pool
+	crypto *crypto.Service
+}
+
+func NewOutboxRepo(
+	pool *pgxpool.Pool,
+	crypto *crypto.Service,
+) *OutboxRepo {
+	return &OutboxRepo{
+		pool:   pool,
+		crypto: crypto,
+	}
+}
+
+func insertOutboxJob(
+	ctx context.Context,
+	tx pgx.Tx,
+	c *crypto.Service,
+	job *domain.OutboxJob,
+) error {
+	raw, err := json.Marshal(job.Params)
+	if err != nil {
+		return err
+	}
+
+	paramsEnc, err := c.Encrypt(raw)
+	if err != nil {
+		return err
+	}
+
+	const q = `
+		INSERT INTO telegram_outbox (
+			idempotency_key,
+			order_id,
+			chat_id,
+			method,
+			params_enc
+		)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (idempotency_key) DO NOTHING
+	`
+
+	if _, err := tx.Exec(ctx, q,
+		job.IdempotencyKey,
+		job.OrderID,
+		job.ChatID,
+		job.Method,
+		paramsEnc,
+	); err != nil {
+		return dbErr("outbox.insert", err)
+	}
+
+	return nil
+}
+
+// Claim leases due jobs. A job is skipped while an older job for the same
+// chat is still waiting, so relays reach each chat in order.
+func (r *OutboxRepo) Claim(
+	ctx context.Context,
+	limit int,
+	lease time.Duration,
+) ([]domain.OutboxJob, error) {
+	const q = `
+		WITH due AS (
+			SELECT o.id
+			FROM telegram_outbox o
+			WHERE o.status = 'pending'
+				AND o.next_attempt_at <= now()
+				AND (o.locked_until IS NULL OR o.locked_until < now())
+				AND NOT EXISTS (
+					SELECT 1
+					FROM telegram_outbox p
+					WHERE p.chat_id = o.chat_id
+						AND p.status = 'pending'
+						AND p.id < o.id
+						AND (p.next_attempt_at > now() OR p.locked_until >= now())
+				)
+			ORDER BY o.id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE telegram_outbox t
+		SET
+			locked_until = now() + make_interval(secs => $2),
+			attempts = t.attempts + 1
+		FROM due
+		WHERE t.id = due.id
+		RETURNING
+			t.id,
+			t.idempotency_key,
+			t.order_id,
+			t.chat_id,
+			t.method,
+			t.params_enc,
+			t.status,
+			t.attempts,
+			t.next_attempt_at,
+			t.last_error,
+			t.created_at,
+			t.sent_at
+	`
+
+	rows, err := r.pool.Query(ctx, q, limit, lease.Seconds())
+	if err != nil {
+		wrapped := dbErr("outbox.claim", err)
+		logger.Log.Errorw("failed to claim outbox jobs",
+			"err", wrapped,
+		)
+		return nil, wrapped
+	}
+	defer rows.Close()
+
+	jobs, err := r.scanJobs(rows, "outbox.claim")
+	if err != nil {
+		return nil, err
+	}
+
+	// RETURNING does not keep the CTE order.
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].ID < jobs[j].ID
+	})
+
+	return jobs, nil
+}
+
+func (r *OutboxRepo) MarkSent(ctx context.Context, id int64) error {
+	const q = `
+		UPDATE telegram_outbox
+		SET
+			status = 'sent',
+			sent_at = now(),
+			locked_until = NULL,
+			last_error = NULL
+		WHERE id = $1
+	`
+
+	if _, err := r.pool.Exec(ctx, q, id); err != nil {
+		wrapped := dbErr("outbox.mark_sent", err)
+		logger.Log.Errorw("failed to mark outbox job sent",
+			"outbox_id", id,
+			"err", wrapped,
+		)
+		return wrapped
+	}
+
+	return nil
+}
+
+func (r *OutboxRepo) MarkRetry(
+	ctx context.Context,
+	id int64,
+	nextAttemptAt time.Time,
+	lastErr string,
+) error {
+	const q = `
+		UPDATE telegram_outbox
+		SET
+			next_attempt_at = $2,
+			last_error = $3,
+			locked_until = NULL
+		WHERE id = $1
+	`
+
+	if _, err := r.pool.Exec(ctx, q, id, nextAttemptAt, lastErr); err != nil {
+		wrapped := dbErr("outbox.mark_retry", err)
+		logger.Log.Errorw("failed to reschedule outbox job",
+			"outbox_id", id,
+			"err", wrapped,
+		)
+		return wrapped
+	}
+
+	return nil
+}
+
+func (r *OutboxRepo) MarkFailed(
+	ctx context.Context,
+	id int64,
+	lastErr string,
+) error {
+	const q = `
+		UPDATE telegram_outbox
+		SET
+			status = 'failed',
+			last_error = $2,
+			locked_until = NULL
+		WHERE id = $1
+	`
+
+	if _, err := r.pool.Exec(ctx, q, id, lastErr); err != nil {
+		wrapped := dbErr("outbox.mark_failed", err)
+		logger.Log.Errorw("failed to mark outbox job failed",
+			"outbox_id", id,
+			"err", wrapped,
+		)
+		return wrapped
+	}
+
+	return nil
+}
+
+func (r *OutboxRepo) GetStuck(
+	ctx context.Context,
+	createdBefore time.Time,
+	limit int,
+) ([]domain.OutboxJob, error) {
+	const q = `
+		SELECT
+			id,
+			idempotency_key,
+			order_id,
+			chat_id,
+			method,
+			params_enc,
+			status,
+			attempts,
+			next_attempt_at,
+			last_error,
+			created_at,
+			sent_at
+		FROM telegram_outbox
+		WHERE status IN ('pending', 'failed')
+			AND created_at < $1
+		ORDER BY created_at ASC
+		LIMIT $2
+	`
+
+	rows, err := r.pool.Query(ctx, q, createdBefore, limit)
+	if err != nil {
+		wrapped := dbErr("outbox.get_stuck", err)
+		logger.Log.Errorw("failed to query stuck outbox jobs",
+			"err", wrapped,
+		)
+		return nil, wrapped
+	}
+	defer rows.Close()
+
+	return r.scanJobs(rows, "outbox.get_stuck")
+}
+
+func (r *OutboxRepo) DeleteSentBefore(
+	ctx context.Context,
+	before time.Time,
+) (int64, error) {
+	const q = `
+		DELETE FROM telegram_outbox
+		WHERE status = 'sent'
+			AND sent_at < $1
+	`
+
+	cmd, err := r.pool.Exec(ctx, q, before)
+	if err != nil {
+		wrapped := dbErr("outbox.delete_sent_before", err)
+		logger.Log.Errorw("failed to delete sent outbox jobs",
+			"err", wrapped,
+		)
+		return 0, wrapped
+	}
+
+	return cmd.RowsAffected(), nil
+}
+
+func (r *OutboxRepo) Release(ctx context.Context, ids []int64) error {
+	const q = `
+		UPDATE telegram_outbox
+		SET
+			locked_until = NULL,
+			attempts = GREATEST(attempts - 1, 0)
+		WHERE id = ANY($1)
+			AND status = 'pending'
+	`
+
+	if _, err := r.pool.Exec(ctx, q, ids); err != nil {
+		wrapped := dbErr("outbox.release", err)
+		logger.Log.Errorw("failed to release outbox jobs",
+			"count", len(ids),
+			"err", wrapped,
+		)
+		return wrapped
+	}
+
+	return nil
+}
+
+func (r *OutboxRepo) scanJobs(
+	rows pgx.Rows,
+	op string,
+) ([]domain.OutboxJob, error) {
+	var out []domain.OutboxJob
+	for rows.Next() {
+		var (
+			job       domain.OutboxJob
+			paramsEnc []byte
+		)
+		if err := rows.Scan(
+			&job.ID,
+			&job.IdempotencyKey,
+			&job.OrderID,
+			&job.ChatID,
+			&job.Method,
+			&paramsEnc,
+			&job.Status,
+			&job.Attempts,
+			&job.NextAttemptAt,
+			&job.LastError,
+			&job.CreatedAt,
+			&job.SentAt,
+		); err != nil {
+			wrapped := dbErr(op+".scan", err)
+			logger.Log.Errorw("failed to scan outbox row",
+				"err", wrapped,
+			)
+			return nil, wrapped
+		}
+
+		// Undecodable params are left nil so the dispatcher can fail the
+		// job instead of the whole batch.
+		raw, err := r.crypto.Decrypt(paramsEnc)
+		if err == nil {
+			err = json.Unmarshal(raw, &job.Params)
+		}
+		if err != nil {
+			job.Params = nil
+			logger.Log.Errorw("failed to decode outbox params",
+				"outbox_id", job.ID,
+				"err", err,
+			)
+		}
+
+		out = append(out, job)
+	}
+
+	if err := rows.Err(); err != nil {
+		wrapped := dbErr(op+".rows", err)
+		logger.Log.Errorw("rows error while iterating outbox jobs",
+			"err", wrapped,
+		)
+		return nil, wrapped
+	}
+
+	return out, nil
+}
@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/m4xvel/monetych_bot/internal/domain"
+	"github.com/m4xvel/monetych_bot/internal/logger"
+)
+
+type ResponseTemplateRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewResponseTemplateRepo(pool *pgxpool.Pool) *ResponseTemplateRepo {
+	return &ResponseTemplateRepo{pool: pool}
+}
+
+func (r *ResponseTemplateRepo) GetAvailable(
+	ctx context.Context,
+	expertID int,
+) ([]domain.ResponseTemplate, error) {
+	const q = `
+		SELECT
+			id,
+			expert_id,
+			title,
+			body,
+			created_at
+		FROM response_templates
+		WHERE is_active = TRUE
+			AND (expert_id IS NULL OR expert_id = $1)
+		ORDER BY expert_id NULLS FIRST, title ASC
+	`
+
+	rows, err := r.pool.Query(ctx, q, expertID)
+	if err != nil {
+		wrapped := dbErr("response_template.get_available", err)
+		logger.Log.Errorw("failed to query response templates",
+			"expert_id", expertID,
+			"err", wrapped,
+		)
+		return nil, wrapped
+	}
+	defer rows.Close()
+
+	var out []domain.ResponseTemplate
+	for rows.Next() {
+		var t domain.ResponseTemplate
+		if err := rows.Scan(
+			&t.ID,
+			&t.ExpertID,
+			&t.Title,
+			&t.Body,
+			&t.CreatedAt,
+		); err != nil {
+			wrapped := dbErr("response_template.scan", err)
+			logger.Log.Errorw("failed to scan response template row",
+				"expert_id", expertID,
+				"err", wrapped,
+			)
+			return nil, wrapped
+		}
+		out = append(out, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		wrapped := dbErr("response_template.rows", err)
+		logger.Log.Errorw("rows error while iterating response templates",
+			"expert_id", expertID,
+			"err", wrapped,
+		)
+		return nil, wrapped
+	}
+
+	return out, nil
+}
+
+func (r *ResponseTemplateRepo) GetByID(
+	ctx context.Context,
+	id int,
+) (*domain.ResponseTemplate, error) {
+	const q = `
+		SELECT
+			id,
+			expert_id,
+			title,
+			body,
+			created_at
+		FROM response_templates
+		WHERE id = $1
+			AND is_active = TRUE
+	`
+
+	var t domain.ResponseTemplate
+	if err := r.pool.QueryRow(ctx, q, id).Scan(
+		&t.ID,
+		&t.ExpertID,
+		&t.Title,
+		&t.Body,
+		&t.CreatedAt,
+	); err != nil {
+		wrapped := dbErr("response_template.get_by_id", err)
+		logger.Log.Errorw("failed to get response template",
+			"template_id", id,
+			"err", wrapped,
+		)
+		return nil, wrapped
+	}
+
+	return &t, nil
+}
@@ -2,7 +2,10 @@ package postgres
 
 import (
 	"context"
+	"errors"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/m4xvel/monetych_bot/internal/apperr"
 	"github.com/m4xvel/monetych_bot/internal/domain"
@@ -91,6 +94,7 @@ func (r *ReviewRepo) Publish(
 			status = $2,
 			published_at = now()
 		WHERE id = $1
+			AND status = $3
 	`
 
 	cmd, err := r.pool.Exec(
@@ -98,6 +102,7 @@ func (r *ReviewRepo) Publish(
 		q,
 		reviewID,
 		domain.ReviewPublished,
+		domain.ReviewRated,
 	)
 	if err != nil {
 		wrapped := dbErr("review.publish", err)
@@ -113,3 +118,418 @@ func (r *ReviewRepo) Publish(
 
 	return nil
 }
+
+const reviewColumns = `
+	r.id,
+	r.order_id,
+	r.rating,
+	r.text,
+	r.status,
+	r.created_at,
+	r.published_at,
+	r.reject_reason,
+	r.moderated_at,
+	r.moderated_by,
+	r.is_anonymous,
+	r.channel_chat_id,
+	r.channel_message_id
+`
+
+func scanReview(row pgx.Row, extra ...any) (*domain.Review, error) {
+	var rv domain.Review
+	dest := []any{
+		&rv.ID,
+		&rv.OrderID,
+		&rv.Rating,
+		&rv.Text,
+		&rv.Status,
+		&rv.CreatedAt,
+		&rv.PublishedAt,
+		&rv.RejectReason,
+		&rv.ModeratedAt,
+		&rv.ModeratedBy,
+		&rv.IsAnonymous,
+		&rv.ChannelChatID,
+		&rv.ChannelMessageID,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	return &rv, nil
+}
+
+func (r *ReviewRepo) GetByID(
+	ctx context.Context,
+	reviewID int,
+) (*domain.Review, error) {
+	q := `SELECT ` + reviewColumns + ` FROM reviews r WHERE r.id = $1`
+
+	rv, err := scanReview(r.pool.QueryRow(ctx, q, reviewID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, dbErrKind("review.get_by_id", apperr.KindNotFound, err)
+		}
+		wrapped := dbErr("review.get_by_id", err)
+		logger.Log.Errorw("failed to get review",
+			"review_id", reviewID,
+			"err", wrapped,
+		)
+		return nil, wrapped
+	}
+
+	return rv, nil
+}
+
+func (r *ReviewRepo) GetPendingModeration(
+	ctx context.Context,
+	limit int,
+) ([]domain.Review, error) {
+	q := `SELECT ` + reviewColumns + `
+		FROM reviews r
+		WHERE r.status = $1
+		ORDER BY r.id ASC
+		LIMIT $2
+	`
+
+	rows, err := r.pool.Query(ctx, q, domain.ReviewWithText, limit)
+	if err != nil {
+		wrapped := dbErr("review.get_pending_moderation", err)
+		logger.Log.Errorw("failed to get reviews pending moderation",
+			"err", wrapped,
+		)
+		return nil, wrapped
+	}
+	defer rows.Close()
+
+	var reviews []domain.Review
+	for rows.Next() {
+		rv, err := scanReview(rows)
+		if err != nil {
+			return nil, dbErr("review.get_pending_moderation", err)
+		}
+		reviews = append(reviews, *rv)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, dbErr("review.get_pending_moderation", err)
+	}
+
+	return reviews, nil
+}
+
+func (r *ReviewRepo) Approve(
+	ctx context.Context,
+	reviewID int,
+	moderatorID int64,
+) error {
+	const q = `
+		UPDATE reviews
+		SET
+			status = $2,
+			published_at = now(),
+			moderated_at = now(),
+			moderated_by = $3
+		WHERE id = $1
+			AND status = $4
+	`
+
+	cmd, err := r.pool.Exec(
+		ctx,
+		q,
+		reviewID,
+		domain.ReviewPublished,
+		moderatorID,
+		domain.ReviewWithText,
+	)
+	if err != nil {
+		wrapped := dbErr("review.approve", err)
+		logger.Log.Errorw("failed to approve review",
+			"err", wrapped,
+		)
+		return wrapped
+	}
+
+	if cmd.RowsAffected() == 0 {
+		return dbErrKind("review.approve", apperr.KindConflict, nil)
+	}
+
+	return nil
+}
+
+func (r *ReviewRepo) Reject(
+	ctx context.Context,
+	reviewID int,
+	moderatorID int64,
+	reason string,
+) error {
+	const q = `
+		UPDATE reviews
+		SET
+			status = $2,
+			reject_reason = $3,
+			moderated_at = now(),
+			moderated_by = $4
+		WHERE id = $1
+			AND status = $5
+	`
+
+	cmd, err := r.pool.Exec(
+		ctx,
+		q,
+		reviewID,
+		domain.ReviewRejected,
+		reason,
+		moderatorID,
+		domain.ReviewWithText,
+	)
+	if err != nil {
+		wrapped := dbErr("review.reject", err)
+		logger.Log.Errorw("failed to reject review",
+			"err", wrapped,
+		)
+		return wrapped
+	}
+
+	if cmd.RowsAffected() == 0 {
+		return dbErrKind("review.reject", apperr.KindConflict, nil)
+	}
+
+	return nil
+}
+
+// SetAnonymous hides the author of a review that is pending or already
+// published. Rejected reviews are never shown, so they are a conflict.
+func (r *ReviewRepo) SetAnonymous(
+	ctx context.Context,
+	orderID int,
+) (*domain.Review, error) {
+	const q = `
+		UPDATE reviews
+		SET is_anonymous = true
+		WHERE order_id = $1
+			AND status IN ($2, $3, $4)
+		RETURNING id, status, channel_chat_id, channel_message_id
+	`
+
+	review := domain.Review{OrderID: orderID, IsAnonymous: true}
+	err := r.pool.QueryRow(
+		ctx,
+		q,
+		orderID,
+		domain.ReviewRated,
+		domain.ReviewWithText,
+		domain.ReviewPublished,
+	).Scan(
+		&review.ID,
+		&review.Status,
+		&review.ChannelChatID,
+		&review.ChannelMessageID,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, dbErrKind("review.set_anonymous", apperr.KindConflict, nil)
+	}
+	if err != nil {
+		wrapped := dbErr("review.set_anonymous", err)
+		logger.Log.Errorw("failed to set review anonymous",
+			"err", wrapped,
+		)
+		return nil, wrapped
+	}
+
+	return &review, nil
+}
+
+func (r *ReviewRepo) SetChannelPost(
+	ctx context.Context,
+	reviewID int,
+	chatID int64,
+	messageID int,
+) error {
+	const q = `
+		UPDATE reviews
+		SET
+			channel_chat_id = $2,
+			channel_message_id = $3
+		WHERE id = $1
+	`
+
+	if _, err := r.pool.Exec(ctx, q, reviewID, chatID, messageID); err != nil {
+		wrapped := dbErr("review.set_channel_post", err)
+		logger.Log.Errorw("failed to save review channel post",
+			"err", wrapped,
+		)
+		return wrapped
+	}
+
+	return nil
+}
+
+func (r *ReviewRepo) ListPublished(
+	ctx context.Context,
+	gameID int,
+	limit int,
+	offset int,
+) ([]domain.PublishedReview, error) {
+	q := `SELECT ` + reviewColumns + `,
+			o.game_name_at_purchase,
+			o.game_type_name_at_purchase,
+			CASE WHEN r.is_anonymous THEN '' ELSE o.user_name_at_purchase END,
+			CASE WHEN r.is_anonymous THEN '' ELSE coalesce(u.img_url, '') END
+		FROM reviews r
+		JOIN orders o ON o.id = r.order_id
+		LEFT JOIN users u ON u.id = o.user_id
+		WHERE r.status = $1
+			AND ($2::int = 0 OR o.game_id = $2)
+		ORDER BY r.published_at DESC, r.id DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	rows, err := r.pool.Query(
+		ctx,
+		q,
+		domain.ReviewPublished,
+		gameID,
+		limit,
+		offset,
+	)
+	if err != nil {
+		wrapped := dbErr("review.list_published", err)
+		logger.Log.Errorw("failed to list published reviews",
+			"game_id", gameID,
+			"err", wrapped,
+		)
+		return nil, wrapped
+	}
+	defer rows.Close()
+
+	var reviews []domain.PublishedReview
+	for rows.Next() {
+		var pr domain.PublishedReview
+		rv, err := scanReview(
+			rows,
+			&pr.GameName,
+			&pr.GameTypeName,
+			&pr.AuthorName,
+			&pr.AuthorPhotoURL,
+		)
+		if err != nil {
+			return nil, dbErr("review.list_published", err)
+		}
+		pr.Review = *rv
+		reviews = append(reviews, pr)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, dbErr("review.list_published", err)
+	}
+
+	return reviews, nil
+}
+
+func (r *ReviewRepo) GetPublishedStats(
+	ctx context.Context,
+	gameID int,
+) (domain.ReviewStats, error) {
+	const q = `
+		SELECT r.rating, count(*)
+		FROM reviews r
+		JOIN orders o ON o.id = r.order_id
+		WHERE r.status = $1
+			AND ($2::int = 0 OR o.game_id = $2)
+		GROUP BY r.rating
+	`
+
+	var stats domain.ReviewStats
+
+	rows, err := r.pool.Query(ctx, q, domain.ReviewPublished, gameID)
+	if err != nil {
+		wrapped := dbErr("review.get_published_stats", err)
+		logger.Log.Errorw("failed to get published review stats",
+			"game_id", gameID,
+			"err", wrapped,
+		)
+		return stats, wrapped
+	}
+	defer rows.Close()
+
+	sum := 0
+	for rows.Next() {
+		var rating, count int
+		if err := rows.Scan(&rating, &count); err != nil {
+			return stats, dbErr("review.get_published_stats", err)
+		}
+		if rating < 1 || rating > len(stats.ByRating) {
+			continue
+		}
+		stats.ByRating[rating-1] = count
+		stats.Count += count
+		sum += rating * count
+	}
+
+	if err := rows.Err(); err != nil {
+		return stats, dbErr("review.get_published_stats", err)
+	}
+
+	if stats.Count > 0 {
+		stats.Average = float64(sum) / float64(stats.Count)
+	}
+
+	return stats, nil
+}
+
+// PublishRatedBefore publishes up to limit ratings left without a text
+// since before the given time and returns them.
+func (r *ReviewRepo) PublishRatedBefore(
+	ctx context.Context,
+	before time.Time,
+	limit int,
+) ([]domain.Review, error) {
+	q := `
+		UPDATE reviews r
+		SET
+			status = $2,
+			published_at = now()
+		WHERE r.id IN (
+			SELECT id
+			FROM reviews
+			WHERE status = $3
+				AND created_at < $1
+			ORDER BY created_at
+			LIMIT $4
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + reviewColumns
+
+	rows, err := r.pool.Query(
+		ctx,
+		q,
+		before,
+		domain.ReviewPublished,
+		domain.ReviewRated,
+		limit,
+	)
+	if err != nil {
+		wrapped := dbErr("review.publish_rated_before", err)
+		logger.Log.Errorw("failed to publish stale rated reviews",
+			"before", before,
+			"err", wrapped,
+		)
+		return nil, wrapped
+	}
+	defer rows.Close()
+
+	var reviews []domain.Review
+	for rows.Next() {
+		rv, err := scanReview(rows)
+		if err != nil {
+			return nil, dbErr("review.publish_rated_before", err)
+		}
+		reviews = append(reviews, *rv)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, dbErr("review.publish_rated_before", err)
+	}
+
+	return reviews, nil
+}
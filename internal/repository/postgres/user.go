@@ -90,15 +90,40 @@ func (r *UserRepo) UpdateVerified(
 	return nil
 }
 
+func (r *UserRepo) UpdateReachable(
+	ctx context.Context,
+	chatID int64,
+	isReachable bool,
+) error {
+	const q = `
+	UPDATE users
+	SET
+		is_reachable = $1,
+		unreachable_since = CASE WHEN $1 THEN NULL ELSE now() END
+	WHERE chat_id = $2
+		AND is_reachable <> $1
+	`
+
+	if _, err := r.pool.Exec(ctx, q, isReachable, chatID); err != nil {
+		wrapped := dbErr("user.update_reachable", err)
+		logger.Log.Errorw("failed to update user reachability",
+			"err", wrapped,
+		)
+		return wrapped
+	}
+
+	return nil
+}
+
 func (r *UserRepo) Get(ctx context.Context, user domain.User) (*domain.User, error) {
 	const q = `
-	SELECT id, chat_id, name, is_verified
+	SELECT id, chat_id, name, is_verified, is_reachable
 	FROM users
 	WHERE chat_id = $1
 	`
 	var u domain.User
 	err := r.pool.QueryRow(ctx, q, user.ChatID).
-		Scan(&u.ID, &u.ChatID, &u.Name, &u.IsVerified)
+		Scan(&u.ID, &u.ChatID, &u.Name, &u.IsVerified, &u.IsReachable)
 
 	if err != nil {
 		wrapped := dbErr("user.get", err)
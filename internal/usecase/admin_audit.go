@@ -0,0 +1,36 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/m4xvel/monetych_bot/internal/domain"
+)
+
+type AdminAuditService struct {
+	repo domain.AdminAuditRepository
+}
+
+func NewAdminAuditService(r domain.AdminAuditRepository) *AdminAuditService {
+	return &AdminAuditService{repo: r}
+}
+
+// Start records a request before it runs, so nothing happens through the
+// admin API without an audit entry.
+func (s *AdminAuditService) Start(ctx context.Context, entry domain.AdminAuditEntry) (int64, error) {
+	return s.repo.Save(ctx, entry)
+}
+
+// Record stores an entry for a request that is already answered.
+func (s *AdminAuditService) Record(ctx context.Context, entry domain.AdminAuditEntry) error {
+	_, err := s.repo.Save(ctx, entry)
+	return err
+}
+
+func (s *AdminAuditService) Finish(
+	ctx context.Context,
+	id int64,
+	statusCode int,
+	details map[string]any,
+) error {
+	return s.repo.Finish(ctx, id, statusCode, details)
+}
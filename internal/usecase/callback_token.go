@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/m4xvel/monetych_bot/internal/apperr"
@@ -23,7 +24,9 @@ func NewCallbackTokenService(
 func (s *CallbackTokenService) Create(
 	ctx context.Context,
 	action string,
+	scope string,
 	payload any,
+	ttl time.Duration,
 ) (string, error) {
 	token := uuid.NewString()
 
@@ -32,11 +35,20 @@ func (s *CallbackTokenService) Create(
 		return "", err
 	}
 
-	err = s.repo.Create(ctx, &domain.CallbackToken{
+	callback := &domain.CallbackToken{
 		Token:   token,
 		Action:  action,
 		Payload: data,
-	})
+	}
+	if scope != "" {
+		callback.Scope = &scope
+	}
+	if ttl > 0 {
+		expiresAt := time.Now().Add(ttl)
+		callback.ExpiresAt = &expiresAt
+	}
+
+	err = s.repo.Create(ctx, callback)
 	if err != nil {
 		return "", err
 	}
@@ -44,7 +56,8 @@ func (s *CallbackTokenService) Create(
 	return token, nil
 }
 
-func (u *CallbackTokenService) Consume(
+// Peek decodes the token's payload into dest without consuming it.
+func (u *CallbackTokenService) Peek(
 	ctx context.Context,
 	token string,
 	action string,
@@ -55,25 +68,55 @@ func (u *CallbackTokenService) Consume(
 		Action: action,
 	}
 
+	if err := u.repo.Get(ctx, cb); err != nil {
+		return err
+	}
+
+	return json.Unmarshal(cb.Payload, dest)
+}
+
+// Consume deletes the token and decodes its payload into dest. The deleted
+// row is returned so a handler that fails transiently can Restore it.
+func (u *CallbackTokenService) Consume(
+	ctx context.Context,
+	token string,
+	action string,
+	dest any,
+) (*domain.CallbackToken, error) {
+	cb := &domain.CallbackToken{
+		Token:  token,
+		Action: action,
+	}
+
 	if err := u.repo.Consume(ctx, cb); err != nil {
 		if errors.Is(err, apperr.ErrNotFound) {
-			return apperr.Wrap(apperr.KindInvalid, "callback_token.consume", err)
+			return nil, apperr.Wrap(apperr.KindInvalid, "callback_token.consume", err)
 		}
-		return err
+		return nil, err
+	}
+
+	if cb.ExpiresAt != nil && time.Now().After(*cb.ExpiresAt) {
+		return nil, apperr.Wrap(apperr.KindExpired, "callback_token.consume", apperr.ErrExpired)
 	}
 
 	if err := json.Unmarshal(cb.Payload, dest); err != nil {
-		return apperr.Wrap(apperr.KindInvalid, "callback_token.payload", err)
+		return nil, apperr.Wrap(apperr.KindInvalid, "callback_token.payload", err)
 	}
-	return nil
+	return cb, nil
 }
 
-func (u *CallbackTokenService) DeleteByActionAndOrderID(
+func (u *CallbackTokenService) Restore(
 	ctx context.Context,
-	action string,
-	orderID int,
+	cb *domain.CallbackToken,
 ) error {
-	return u.repo.DeleteByActionAndOrderID(ctx, action, orderID)
+	return u.repo.Create(ctx, cb)
+}
+
+func (u *CallbackTokenService) DeleteByScope(
+	ctx context.Context,
+	scope string,
+) error {
+	return u.repo.DeleteByScope(ctx, scope)
 }
 
 func (u *CallbackTokenService) Delete(
@@ -83,3 +126,13 @@ func (u *CallbackTokenService) Delete(
 ) error {
 	return u.repo.Delete(ctx, token, action)
 }
+
+// expiredCallbackRetention keeps expired tokens around for a while, so a
+// late press is answered as expired rather than as an unknown button.
+const expiredCallbackRetention = 7 * 24 * time.Hour
+
+func (u *CallbackTokenService) DeleteExpired(
+	ctx context.Context,
+) (int64, error) {
+	return u.repo.DeleteExpired(ctx, time.Now().Add(-expiredCallbackRetention))
+}
@@ -5,3 +5,7 @@ import "github.com/m4xvel/monetych_bot/internal/apperr"
 var ErrNotFound = apperr.ErrNotFound
 var ErrUserAlreadyExists = apperr.ErrConflict
 var ErrInvalidToken = apperr.ErrInvalid
+var ErrTransitionNotAllowed = &apperr.Error{
+	Kind: apperr.KindConflict,
+	Msg:  "order status transition not allowed",
+}
@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/m4xvel/monetych_bot/internal/domain"
+)
+
+const (
+	// Every expert starts with ratingPriorWeight reviews of ratingPrior
+	// stars before their own ratings count.
+	ratingPriorWeight = 10
+	ratingPrior       = 4.5
+	// expertLoadPenalty is what each accepted or confirmed order costs in
+	// stars, so busy experts give way to free ones with a similar rating.
+	expertLoadPenalty = 0.1
+)
+
+type ExpertRatingService struct {
+	repo        domain.ExpertRatingRepository
+	ratings     map[int]domain.ExpertRating
+	refreshedAt time.Time
+	mu          sync.RWMutex
+}
+
+func NewExpertRatingService(
+	r domain.ExpertRatingRepository,
+) *ExpertRatingService {
+	return &ExpertRatingService{
+		repo:    r,
+		ratings: make(map[int]domain.ExpertRating),
+	}
+}
+
+// Refresh recomputes the aggregates and reloads the cache.
+func (s *ExpertRatingService) Refresh(ctx context.Context) error {
+	if err := s.repo.Refresh(ctx); err != nil {
+		return err
+	}
+
+	rows, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	ratings := make(map[int]domain.ExpertRating, len(rows))
+	for _, r := range rows {
+		ratings[r.ExpertID] = r
+	}
+
+	s.mu.Lock()
+	s.ratings = ratings
+	s.refreshedAt = time.Now()
+	s.mu.Unlock()
+
+	return nil
+}
+
+// RefreshedAt is when the cache was last reloaded; zero before the first
+// successful refresh.
+func (s *ExpertRatingService) RefreshedAt() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.refreshedAt
+}
+
+func (s *ExpertRatingService) Get(expertID int) (domain.ExpertRating, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	r, ok := s.ratings[expertID]
+	return r, ok
+}
+
+// Rank orders experts by their smoothed rating minus a penalty for the
+// orders they are already working on. The rating is a Bayesian average that
+// pulls experts with few reviews towards the prior, so a single 5★ doesn't
+// beat hundreds of 4.9★ and unrated experts start at the prior rather than
+// last. activeOrders may be nil.
+func (s *ExpertRatingService) Rank(
+	experts []domain.Expert,
+	activeOrders map[int]int,
+) []domain.Expert {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	score := make(map[int]float64, len(experts))
+	for _, e := range experts {
+		score[e.ID] = smoothedRating(s.ratings[e.ID]) -
+			expertLoadPenalty*float64(activeOrders[e.ID])
+	}
+
+	ranked := make([]domain.Expert, len(experts))
+	copy(ranked, experts)
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i].ID, ranked[j].ID
+		if score[a] != score[b] {
+			return score[a] > score[b]
+		}
+		if activeOrders[a] != activeOrders[b] {
+			return activeOrders[a] < activeOrders[b]
+		}
+		if s.ratings[a].Count != s.ratings[b].Count {
+			return s.ratings[a].Count > s.ratings[b].Count
+		}
+		return a < b
+	})
+
+	return ranked
+}
+
+func smoothedRating(r domain.ExpertRating) float64 {
+	return (ratingPriorWeight*ratingPrior + r.Average*float64(r.Count)) /
+		(ratingPriorWeight + float64(r.Count))
+}
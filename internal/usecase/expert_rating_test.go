@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/m4xvel/monetych_bot/internal/domain"
+)
+
+type stubExpertRatingRepo struct {
+	rows []domain.ExpertRating
+}
+
+func (r stubExpertRatingRepo) Refresh(context.Context) error { return nil }
+
+func (r stubExpertRatingRepo) GetAll(context.Context) ([]domain.ExpertRating, error) {
+	return r.rows, nil
+}
+
+func TestExpertRatingRank(t *testing.T) {
+	tests := []struct {
+		name         string
+		ratings      []domain.ExpertRating
+		activeOrders map[int]int
+		experts      []int
+		want         []int
+	}{
+		{
+			name: "many good reviews beat a single perfect one",
+			ratings: []domain.ExpertRating{
+				{ExpertID: 1, Count: 1, Average: 5},
+				{ExpertID: 2, Count: 200, Average: 4.9},
+			},
+			experts: []int{1, 2},
+			want:    []int{2, 1},
+		},
+		{
+			name: "unrated experts start at the mean, not last",
+			ratings: []domain.ExpertRating{
+				{ExpertID: 1, Count: 50, Average: 4.9},
+				{ExpertID: 2, Count: 50, Average: 3},
+			},
+			experts: []int{2, 3, 1},
+			want:    []int{1, 3, 2},
+		},
+		{
+			name: "busy experts give way to free ones",
+			ratings: []domain.ExpertRating{
+				{ExpertID: 1, Count: 100, Average: 4.8},
+				{ExpertID: 2, Count: 100, Average: 4.7},
+			},
+			activeOrders: map[int]int{1: 3},
+			experts:      []int{1, 2},
+			want:         []int{2, 1},
+		},
+		{
+			name:    "no ratings keeps id order",
+			experts: []int{3, 1, 2},
+			want:    []int{1, 2, 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewExpertRatingService(stubExpertRatingRepo{rows: tt.ratings})
+			if err := s.Refresh(context.Background()); err != nil {
+				t.Fatalf("Refresh: %v", err)
+			}
+
+			experts := make([]domain.Expert, 0, len(tt.experts))
+			for _, id := range tt.experts {
+				experts = append(experts, domain.Expert{ID: id})
+			}
+
+			var got []int
+			for _, e := range s.Rank(experts, tt.activeOrders) {
+				got = append(got, e.ID)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("Rank = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/m4xvel/monetych_bot/internal/domain"
+	"github.com/m4xvel/monetych_bot/internal/fraud"
+	"github.com/m4xvel/monetych_bot/internal/logger"
+)
+
+type FraudHitService struct {
+	repo   domain.FraudHitRepository
+	engine *fraud.Engine
+}
+
+func NewFraudHitService(
+	r domain.FraudHitRepository,
+	engine *fraud.Engine,
+) *FraudHitService {
+	return &FraudHitService{
+		repo:   r,
+		engine: engine,
+	}
+}
+
+func (s *FraudHitService) Inspect(
+	ctx context.Context,
+	orderID int,
+	role domain.SenderRole,
+	chatID int64,
+	messageID int,
+	text *string,
+) []fraud.Hit {
+	if text == nil {
+		return nil
+	}
+
+	hits := s.engine.Check(*text)
+	for _, hit := range hits {
+		logger.Log.Warnw("fraud rule matched",
+			"order_id", orderID,
+			"sender_role", role,
+			"rule", hit.Rule,
+			"action", hit.Action,
+		)
+
+		if err := s.repo.Save(ctx, domain.FraudHit{
+			OrderID:    orderID,
+			SenderRole: role,
+			ChatID:     chatID,
+			MessageID:  messageID,
+			Rule:       hit.Rule,
+			Action:     string(hit.Action),
+		}); err != nil {
+			logger.Log.Errorw("failed to record fraud hit",
+				"order_id", orderID,
+				"rule", hit.Rule,
+				"err", err,
+			)
+		}
+	}
+
+	return hits
+}
+
+func (s *FraudHitService) GetByOrder(
+	ctx context.Context,
+	orderID int,
+) ([]domain.FraudHit, error) {
+	return s.repo.GetByOrder(ctx, orderID)
+}
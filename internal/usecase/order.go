@@ -4,6 +4,7 @@ import (
 	"context"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/m4xvel/monetych_bot/internal/domain"
 	"github.com/m4xvel/monetych_bot/internal/logger"
@@ -187,6 +188,38 @@ func (s *OrderService) SetDeclinedStatus(ctx context.Context, orderID int) error
 	)
 }
 
+// CancelBySupport cancels an order on behalf of support from whatever
+// unfinished status it is in.
+func (s *OrderService) CancelBySupport(ctx context.Context, order domain.Order) error {
+	if !domain.CanSupportTransition(order.Status, domain.OrderCanceled) {
+		return ErrTransitionNotAllowed
+	}
+
+	err := s.orderRepo.UpdateStatus(
+		ctx,
+		domain.Order{
+			ID:     order.ID,
+			Status: domain.OrderCanceled,
+		},
+		order.Status,
+	)
+	if err != nil {
+		logger.Log.Errorw("failed to cancel order by support",
+			"order_id", order.ID,
+			"from", order.Status,
+			"err", err,
+		)
+		return err
+	}
+
+	logger.Log.Infow("order canceled by support",
+		"order_id", order.ID,
+		"from", order.Status,
+	)
+
+	return nil
+}
+
 func (s *OrderService) GetOrderByID(ctx context.Context,
 	orderID int) (*domain.Order, error) {
 	return s.orderRepo.Get(ctx, orderID)
@@ -216,3 +249,75 @@ func (s *OrderService) FindByID(
 ) (*domain.OrderFull, error) {
 	return s.orderRepo.FindByID(ctx, orderID)
 }
+
+func (s *OrderService) PauseActiveByUser(
+	ctx context.Context,
+	chatID int64,
+) ([]domain.Order, error) {
+	orders, err := s.orderRepo.PauseActiveByUser(ctx, chatID)
+	if err != nil {
+		logger.Log.Errorw("failed to pause active order",
+			"user_chat_id", chatID,
+			"err", err,
+		)
+		return nil, err
+	}
+
+	for _, order := range orders {
+		logger.Log.Infow("order paused",
+			"order_id", order.ID,
+			"user_chat_id", chatID,
+		)
+	}
+
+	return orders, nil
+}
+
+func (s *OrderService) ResumeActiveByUser(
+	ctx context.Context,
+	chatID int64,
+) ([]domain.Order, error) {
+	orders, err := s.orderRepo.ResumeActiveByUser(ctx, chatID)
+	if err != nil {
+		logger.Log.Errorw("failed to resume active order",
+			"user_chat_id", chatID,
+			"err", err,
+		)
+		return nil, err
+	}
+
+	for _, order := range orders {
+		logger.Log.Infow("order resumed",
+			"order_id", order.ID,
+			"user_chat_id", chatID,
+		)
+	}
+
+	return orders, nil
+}
+
+func (s *OrderService) CancelPausedBefore(
+	ctx context.Context,
+	before time.Time,
+) ([]domain.Order, error) {
+	return s.orderRepo.CancelPausedBefore(ctx, before)
+}
+
+func (s *OrderService) ClaimReviewReminders(
+	ctx context.Context,
+	before time.Time,
+	limit int,
+) ([]domain.Order, error) {
+	return s.orderRepo.ClaimReviewReminders(ctx, before, limit)
+}
+
+func (s *OrderService) CountActiveByExpert(ctx context.Context) (map[int]int, error) {
+	return s.orderRepo.CountActiveByExpert(ctx)
+}
+
+func (s *OrderService) List(
+	ctx context.Context,
+	filter domain.OrderFilter,
+) ([]domain.Order, int, error) {
+	return s.orderRepo.List(ctx, filter)
+}
@@ -27,6 +27,8 @@ func (s *OrderChatMessageService) SaveUserMessage(
 	msgType domain.MessageType,
 	text *string,
 	media map[string]any,
+	isRedacted bool,
+	relay *domain.OutboxJob,
 ) error {
 	msg := &domain.OrderChatMessages{
 		OrderID:      orderID,
@@ -37,9 +39,10 @@ func (s *OrderChatMessageService) SaveUserMessage(
 		MessageType:  msgType,
 		Text:         text,
 		Media:        media,
+		IsRedacted:   isRedacted,
 	}
 
-	return s.repo.Save(ctx, msg)
+	return s.repo.Save(ctx, msg, relay)
 }
 
 func (s *OrderChatMessageService) SaveExpertMessage(
@@ -51,6 +54,7 @@ func (s *OrderChatMessageService) SaveExpertMessage(
 	msgType domain.MessageType,
 	text *string,
 	media map[string]any,
+	relay *domain.OutboxJob,
 ) error {
 	msg := &domain.OrderChatMessages{
 		OrderID:        orderID,
@@ -63,5 +67,5 @@ func (s *OrderChatMessageService) SaveExpertMessage(
 		Media:          media,
 	}
 
-	return s.repo.Save(ctx, msg)
+	return s.repo.Save(ctx, msg, relay)
 }
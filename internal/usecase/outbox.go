@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/m4xvel/monetych_bot/internal/domain"
+)
+
+type OutboxService struct {
+	repo domain.OutboxRepository
+}
+
+func NewOutboxService(r domain.OutboxRepository) *OutboxService {
+	return &OutboxService{repo: r}
+}
+
+func (s *OutboxService) Claim(
+	ctx context.Context,
+	limit int,
+	lease time.Duration,
+) ([]domain.OutboxJob, error) {
+	return s.repo.Claim(ctx, limit, lease)
+}
+
+func (s *OutboxService) MarkSent(ctx context.Context, id int64) error {
+	return s.repo.MarkSent(ctx, id)
+}
+
+func (s *OutboxService) MarkRetry(
+	ctx context.Context,
+	id int64,
+	nextAttemptAt time.Time,
+	lastErr string,
+) error {
+	return s.repo.MarkRetry(ctx, id, nextAttemptAt, lastErr)
+}
+
+func (s *OutboxService) MarkFailed(
+	ctx context.Context,
+	id int64,
+	lastErr string,
+) error {
+	return s.repo.MarkFailed(ctx, id, lastErr)
+}
+
+func (s *OutboxService) GetStuck(
+	ctx context.Context,
+	olderThan time.Duration,
+	limit int,
+) ([]domain.OutboxJob, error) {
+	return s.repo.GetStuck(ctx, time.Now().Add(-olderThan), limit)
+}
+
+func (s *OutboxService) CleanupSent(
+	ctx context.Context,
+	retention time.Duration,
+) (int64, error) {
+	return s.repo.DeleteSentBefore(ctx, time.Now().Add(-retention))
+}
+
+func (s *OutboxService) Release(ctx context.Context, ids []int64) error {
+	return s.repo.Release(ctx, ids)
+}
@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+
+	"github.com/m4xvel/monetych_bot/internal/apperr"
+	"github.com/m4xvel/monetych_bot/internal/domain"
+)
+
+type ResponseTemplateService struct {
+	repo domain.ResponseTemplateRepository
+}
+
+func NewResponseTemplateService(
+	r domain.ResponseTemplateRepository,
+) *ResponseTemplateService {
+	return &ResponseTemplateService{repo: r}
+}
+
+type TemplateValues struct {
+	Game     string
+	GameType string
+	Token    string
+	UserName string
+	OrderID  string
+}
+
+func (s *ResponseTemplateService) ListForExpert(
+	ctx context.Context,
+	expertID int,
+) ([]domain.ResponseTemplate, error) {
+	return s.repo.GetAvailable(ctx, expertID)
+}
+
+func (s *ResponseTemplateService) Render(
+	ctx context.Context,
+	templateID int,
+	expertID int,
+	values TemplateValues,
+) (*domain.ResponseTemplate, string, error) {
+	tpl, err := s.repo.GetByID(ctx, templateID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if tpl.ExpertID != nil && *tpl.ExpertID != expertID {
+		return nil, "", apperr.Wrap(
+			apperr.KindForbidden,
+			"response_template.render",
+			apperr.ErrForbidden,
+		)
+	}
+
+	replacer := strings.NewReplacer(
+		"{game}", values.Game,
+		"{type}", values.GameType,
+		"{token}", values.Token,
+		"{user_name}", values.UserName,
+		"{order_id}", values.OrderID,
+	)
+
+	return tpl, replacer.Replace(tpl.Body), nil
+}
@@ -3,6 +3,7 @@ package usecase
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/m4xvel/monetych_bot/internal/apperr"
 	"github.com/m4xvel/monetych_bot/internal/domain"
@@ -106,3 +107,107 @@ func (r *ReviewService) Publish(
 
 	return nil
 }
+
+func (r *ReviewService) GetByID(
+	ctx context.Context,
+	reviewID int,
+) (*domain.Review, error) {
+	return r.repo.GetByID(ctx, reviewID)
+}
+
+func (r *ReviewService) GetPendingModeration(
+	ctx context.Context,
+	limit int,
+) ([]domain.Review, error) {
+	return r.repo.GetPendingModeration(ctx, limit)
+}
+
+func (r *ReviewService) Approve(
+	ctx context.Context,
+	reviewID int,
+	moderatorID int64,
+) error {
+	if err := r.repo.Approve(ctx, reviewID, moderatorID); err != nil {
+		return err
+	}
+
+	logger.Log.Infow("review approved",
+		"review_id", reviewID,
+		"moderator_id", moderatorID,
+	)
+
+	return nil
+}
+
+func (r *ReviewService) Reject(
+	ctx context.Context,
+	reviewID int,
+	moderatorID int64,
+	reason string,
+) error {
+	if err := r.repo.Reject(ctx, reviewID, moderatorID, reason); err != nil {
+		return err
+	}
+
+	logger.Log.Infow("review rejected",
+		"review_id", reviewID,
+		"moderator_id", moderatorID,
+		"reason", reason,
+	)
+
+	return nil
+}
+
+func (r *ReviewService) SetAnonymous(
+	ctx context.Context,
+	orderID int,
+) (*domain.Review, error) {
+	return r.repo.SetAnonymous(ctx, orderID)
+}
+
+func (r *ReviewService) SetChannelPost(
+	ctx context.Context,
+	reviewID int,
+	chatID int64,
+	messageID int,
+) error {
+	return r.repo.SetChannelPost(ctx, reviewID, chatID, messageID)
+}
+
+// ListPublished pages through published reviews, newest first. A zero
+// gameID lists reviews for all games.
+func (r *ReviewService) ListPublished(
+	ctx context.Context,
+	gameID int,
+	limit int,
+	offset int,
+) ([]domain.PublishedReview, error) {
+	return r.repo.ListPublished(ctx, gameID, limit, offset)
+}
+
+func (r *ReviewService) GetPublishedStats(
+	ctx context.Context,
+	gameID int,
+) (domain.ReviewStats, error) {
+	return r.repo.GetPublishedStats(ctx, gameID)
+}
+
+func (r *ReviewService) PublishRatedBefore(
+	ctx context.Context,
+	before time.Time,
+	limit int,
+) ([]domain.Review, error) {
+	reviews, err := r.repo.PublishRatedBefore(ctx, before, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rv := range reviews {
+		logger.Log.Infow("review auto published",
+			"review_id", rv.ID,
+			"order_id", rv.OrderID,
+		)
+	}
+
+	return reviews, nil
+}
@@ -108,3 +108,25 @@ func (s *UserService) SetVerified(
 
 	return nil
 }
+
+func (s *UserService) SetReachable(
+	ctx context.Context,
+	chatID int64,
+	isReachable bool,
+) error {
+	if err := s.users.UpdateReachable(ctx, chatID, isReachable); err != nil {
+		logger.Log.Errorw("failed to update user reachability",
+			"chat_id", chatID,
+			"is_reachable", isReachable,
+			"err", err,
+		)
+		return err
+	}
+
+	logger.Log.Infow("user reachability changed",
+		"chat_id", chatID,
+		"is_reachable", isReachable,
+	)
+
+	return nil
+}
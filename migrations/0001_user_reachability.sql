@@ -0,0 +1,10 @@
+ALTER TABLE users
+	ADD COLUMN IF NOT EXISTS is_reachable boolean NOT NULL DEFAULT true,
+	ADD COLUMN IF NOT EXISTS unreachable_since timestamptz;
+
+ALTER TABLE orders
+	ADD COLUMN IF NOT EXISTS paused_at timestamptz;
+
+CREATE INDEX IF NOT EXISTS orders_paused_at_idx
+	ON orders (paused_at)
+	WHERE paused_at IS NOT NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE order_chat_messages
+	ADD COLUMN IF NOT EXISTS is_redacted boolean NOT NULL DEFAULT false;
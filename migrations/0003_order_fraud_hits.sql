@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS order_fraud_hits (
+	id bigserial PRIMARY KEY,
+	order_id integer NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+	sender_role text NOT NULL,
+	chat_id bigint NOT NULL,
+	message_id integer NOT NULL,
+	rule text NOT NULL,
+	action text NOT NULL,
+	created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS order_fraud_hits_order_id_idx
+	ON order_fraud_hits (order_id);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS response_templates (
+	id serial PRIMARY KEY,
+	expert_id integer REFERENCES experts (id) ON DELETE CASCADE,
+	title text NOT NULL,
+	body text NOT NULL,
+	is_active boolean NOT NULL DEFAULT TRUE,
+	created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS response_templates_expert_id_idx
+	ON response_templates (expert_id);
+
+-- Placeholders filled on send: {game}, {type}, {token}, {user_name}, {order_id}.
@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS telegram_outbox (
+	id bigserial PRIMARY KEY,
+	idempotency_key text NOT NULL UNIQUE,
+	order_id integer REFERENCES orders (id) ON DELETE SET NULL,
+	chat_id bigint NOT NULL,
+	method text NOT NULL,
+	params_enc bytea NOT NULL,
+	status text NOT NULL DEFAULT 'pending',
+	attempts integer NOT NULL DEFAULT 0,
+	next_attempt_at timestamptz NOT NULL DEFAULT now(),
+	locked_until timestamptz,
+	last_error text,
+	created_at timestamptz NOT NULL DEFAULT now(),
+	sent_at timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS telegram_outbox_pending_idx
+	ON telegram_outbox (chat_id, id)
+	WHERE status = 'pending';
+
+CREATE INDEX IF NOT EXISTS telegram_outbox_created_at_idx
+	ON telegram_outbox (created_at)
+	WHERE status <> 'sent';
@@ -0,0 +1,22 @@
+ALTER TABLE callback_tokens
+	ADD COLUMN IF NOT EXISTS expires_at timestamptz;
+
+-- Same TTLs as the actions in internal/delivery/telegram/callback_action.go.
+UPDATE callback_tokens
+SET expires_at = now() + interval '7 days'
+WHERE expires_at IS NULL
+  AND action IN ('accept_privacy', 'rate', 'verify');
+
+UPDATE callback_tokens
+SET expires_at = now() + interval '1 day'
+WHERE expires_at IS NULL
+  AND action IN ('game', 'type', 'order', 'show_media');
+
+UPDATE callback_tokens
+SET expires_at = now() + interval '1 hour'
+WHERE expires_at IS NULL
+  AND action = 'tpl';
+
+CREATE INDEX IF NOT EXISTS callback_tokens_expires_at_idx
+	ON callback_tokens (expires_at)
+	WHERE expires_at IS NOT NULL;
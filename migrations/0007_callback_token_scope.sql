@@ -0,0 +1,23 @@
+ALTER TABLE callback_tokens
+	ADD COLUMN IF NOT EXISTS scope text;
+
+UPDATE callback_tokens
+SET scope = 'order:' || (payload->>'order_id') || ':' ||
+	CASE action
+		WHEN 'accept' THEN 'request'
+		WHEN 'cancel' THEN 'request'
+		WHEN 'verify' THEN 'verify'
+		WHEN 'rate' THEN 'rate'
+		WHEN 'tpl' THEN 'templates'
+		ELSE 'panel'
+	END
+WHERE scope IS NULL
+  AND payload ? 'order_id'
+  AND action IN (
+	'accept', 'cancel', 'confirmed', 'declined', 'confirmed_reaffirm',
+	'declined_reaffirm', 'back', 'verification', 'verify', 'rate', 'tpl'
+  );
+
+CREATE INDEX IF NOT EXISTS callback_tokens_scope_idx
+	ON callback_tokens (scope)
+	WHERE scope IS NOT NULL;
@@ -0,0 +1,8 @@
+ALTER TABLE reviews
+	ADD COLUMN IF NOT EXISTS reject_reason text,
+	ADD COLUMN IF NOT EXISTS moderated_at timestamptz,
+	ADD COLUMN IF NOT EXISTS moderated_by bigint;
+
+CREATE INDEX IF NOT EXISTS reviews_pending_moderation_idx
+	ON reviews (id)
+	WHERE status = 'with_text';
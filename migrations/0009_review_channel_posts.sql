@@ -0,0 +1,4 @@
+ALTER TABLE reviews
+	ADD COLUMN IF NOT EXISTS is_anonymous boolean NOT NULL DEFAULT false,
+	ADD COLUMN IF NOT EXISTS channel_chat_id bigint,
+	ADD COLUMN IF NOT EXISTS channel_message_id integer;
@@ -0,0 +1,20 @@
+-- Refreshed by the bot in the background; only published reviews are counted.
+CREATE MATERIALIZED VIEW IF NOT EXISTS expert_ratings AS
+SELECT
+	o.expert_id,
+	count(*) AS rating_count,
+	avg(r.rating)::float8 AS average,
+	count(*) FILTER (
+		WHERE r.created_at >= now() - interval '30 days'
+	) AS recent_count,
+	coalesce(avg(r.rating) FILTER (
+		WHERE r.created_at >= now() - interval '30 days'
+	), 0)::float8 AS recent_average
+FROM reviews r
+JOIN orders o ON o.id = r.order_id
+WHERE o.expert_id IS NOT NULL
+  AND r.status = 'published'
+GROUP BY o.expert_id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS expert_ratings_expert_id_idx
+	ON expert_ratings (expert_id);
@@ -0,0 +1,16 @@
+ALTER TABLE orders
+	ADD COLUMN IF NOT EXISTS review_reminder_sent_at timestamptz;
+
+-- Orders completed before reminders existed are not reminded about.
+UPDATE orders
+SET review_reminder_sent_at = now()
+WHERE status = 'completed'
+  AND review_reminder_sent_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS orders_review_reminder_idx
+	ON orders (updated_at)
+	WHERE status = 'completed' AND review_reminder_sent_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS reviews_rated_idx
+	ON reviews (created_at)
+	WHERE status = 'rated';
@@ -0,0 +1,21 @@
+-- Entries are written before the request runs; status_code stays NULL when
+-- the outcome could not be recorded.
+CREATE TABLE IF NOT EXISTS admin_audit_log (
+	id bigserial PRIMARY KEY,
+	actor text NOT NULL,
+	action text NOT NULL,
+	order_id integer,
+	method text NOT NULL,
+	path text NOT NULL,
+	status_code integer,
+	remote_ip text NOT NULL,
+	details jsonb NOT NULL DEFAULT '{}'::jsonb,
+	created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS admin_audit_log_created_at_idx
+	ON admin_audit_log (created_at);
+
+CREATE INDEX IF NOT EXISTS admin_audit_log_order_id_idx
+	ON admin_audit_log (order_id)
+	WHERE order_id IS NOT NULL;
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"fmt"
+	"strings"
 )
 
 type Dynamic struct {
@@ -38,6 +39,14 @@ type Messages struct {
 	ThanksForReviewText    string
 	ChatClosedText         string
 	WriteReviewText        string
+	UserUnblockedBotText   string
+	OrderAutoCanceledText  string
+	FraudWarningText       string
+	FraudBlockedText       string
+	TemplatesListText      string
+	TemplatesEmptyText     string
+	TemplateOnlyInThread   string
+	FloodWarningText       string
 
 	AgreeButtonText                    string
 	BackButtonText                     string
@@ -49,6 +58,31 @@ type Messages struct {
 	VerificationRequestSentToast       string
 	VerificationRequestReceivedToast   string
 	MediaSentToast                     string
+	TemplateSentToast                  string
+	TemplateUnavailableToast           string
+	CallbackExpiredToast               string
+	ReviewSentToModerationText         string
+	ReviewApproveButtonText            string
+	ReviewRejectButtonText             string
+	ReviewRejectReasons                []string
+	ReviewAlreadyModeratedToast        string
+	ReviewApprovedUserText             string
+	ModerationQueueEmptyText           string
+	ReviewAnonymousButtonText          string
+	ReviewAnonymousToast               string
+	ReviewAnonymousPublishedToast      string
+	ReviewAnonymousRejectedToast       string
+	ReviewAnonymousAuthor              string
+	ReviewsEmptyText                   string
+	ReviewsAllGamesButtonText          string
+	ReviewsPrevButtonText              string
+	ReviewsNextButtonText              string
+	ReviewReminderText                 string
+	OrderCanceledBySupportText         string
+	ExpertOrderCanceledBySupportText   string
+	OutboxEmptyText                    string
+	OutboxStuckHeader                  string
+	OutboxStuckLineTemplate            string
 	SearchTokenPromptText              string
 	SearchNotFoundText                 string
 	SearchShowMediaButtonTemplate      string
@@ -70,6 +104,8 @@ type Messages struct {
 	SearchExpertChatIDLineTemplate     string
 	SearchExpertActiveYes              string
 	SearchExpertActiveNo               string
+	SearchExpertRatingLineTemplate     string
+	SearchExpertNoRatingText           string
 	SearchUserStateHeader              string
 	SearchUserStateLineTemplate        string
 	SearchUserStateUpdatedLineTemplate string
@@ -80,6 +116,9 @@ type Messages struct {
 	ChatMessageHeaderTemplate          string
 	ChatTextLineTemplate               string
 	ChatOtherLine                      string
+	ChatRedactedLine                   string
+	SearchFraudHeader                  string
+	SearchFraudLineTemplate            string
 	ChatQuoteBlockTemplate             string
 	OrderStatusCreatedText             string
 	OrderStatusAcceptedText            string
@@ -129,6 +168,14 @@ func NewMessages(privacyPolicyURL, publicOfferURL string) *Messages {
 		ThanksForReviewText:    "Спасибо за отзыв! Это очень важно для нас 🙏",
 		ChatClosedText:         "Чат завершён!\n\nОцени наш сервис от 1 до 5 ⭐",
 		WriteReviewText:        "Теперь напишите ваш отзыв ✍️",
+		UserUnblockedBotText:   "✅ Клиент снова на связи, заявка возобновлена.",
+		OrderAutoCanceledText:  "🚫 Заявка автоматически отменена: клиент заблокировал бота и не вернулся.",
+		FraudWarningText:       "⚠️ Напоминаем: обмен контактами и проведение сделки вне бота запрещены. Это небезопасно и лишает защиты поддержки.",
+		FraudBlockedText:       "🚫 Сообщение не доставлено: в нём есть контакты или ссылки для связи вне бота.",
+		TemplatesListText:      "📋 Выбери шаблон ответа:",
+		TemplatesEmptyText:     "Шаблонов пока нет.",
+		TemplateOnlyInThread:   "Команда /tpl работает только в ветке активной заявки.",
+		FloodWarningText:       "⏳ Слишком много сообщений подряд. Сделай паузу - лишние сообщения не доставлены.",
 
 		AgreeButtonText:                  "Соглашаюсь",
 		BackButtonText:                   "⬅️ Вернуться назад",
@@ -144,6 +191,30 @@ func NewMessages(privacyPolicyURL, publicOfferURL string) *Messages {
 		VerificationRequestSentToast:       "Отправлено пользователю",
 		VerificationRequestReceivedToast:   "Запрос получен",
 		MediaSentToast:                     "Медиа отправлены",
+		TemplateSentToast:                  "Шаблон отправлен клиенту",
+		TemplateUnavailableToast:           "Шаблон недоступен",
+		CallbackExpiredToast:               "Эта кнопка устарела. Отправьте /start, чтобы открыть меню заново.",
+		ReviewSentToModerationText:         "Спасибо за отзыв! 🙏 Он появится после проверки модератором.",
+		ReviewApproveButtonText:            "✅ Опубликовать",
+		ReviewRejectButtonText:             "❌ Отклонить",
+		ReviewAlreadyModeratedToast:        "Отзыв уже обработан",
+		ReviewApprovedUserText:             "Твой отзыв опубликован. Спасибо! ⭐",
+		ModerationQueueEmptyText:           "Отзывов на модерации нет ✅",
+		ReviewAnonymousButtonText:          "🙈 Не указывать моё имя",
+		ReviewAnonymousToast:               "Отзыв будет опубликован без имени",
+		ReviewAnonymousPublishedToast:      "Имя убрано из опубликованного отзыва",
+		ReviewAnonymousRejectedToast:       "Этот отзыв не публикуется",
+		ReviewAnonymousAuthor:              "Анонимный клиент",
+		ReviewsEmptyText:                   "Здесь пока нет отзывов.",
+		ReviewsAllGamesButtonText:          "🎮 Все игры",
+		ReviewsPrevButtonText:              "◀️ Назад",
+		ReviewsNextButtonText:              "Вперёд ▶️",
+		ReviewReminderText:                 "Как прошла сделка? 🙂\n\nОцени наш сервис от 1 до 5 ⭐ - это займёт пару секунд.",
+		OrderCanceledBySupportText:         "Поддержка отменила заявку 😕\n\nЕсли есть вопросы - напиши в поддержку.",
+		ExpertOrderCanceledBySupportText:   "🚫 Заявка отменена поддержкой.",
+		OutboxEmptyText:                    "Зависших отправок нет ✅",
+		OutboxStuckHeader:                  "📮 <b>Зависшие отправки</b>\n\n",
+		OutboxStuckLineTemplate:            "<b>#%d</b> %s · сделка %s · чат <code>%d</code>\n%s, попыток: %d, создано %s\n<i>%s</i>\n\n",
 		SearchTokenPromptText:              "Укажите токен.\nПример:\n/search ZW6T-HJTK-6WY2",
 		SearchNotFoundText:                 "❌ Ничего не найдено по указанному токену",
 		SearchShowMediaButtonTemplate:      "📎 Показать медиа (%d)",
@@ -165,6 +236,8 @@ func NewMessages(privacyPolicyURL, publicOfferURL string) *Messages {
 		SearchExpertChatIDLineTemplate:     "Chat ID: <code>%d</code>\n",
 		SearchExpertActiveYes:              "Активен: ✅\n",
 		SearchExpertActiveNo:               "Активен: ❌\n",
+		SearchExpertRatingLineTemplate:     "Рейтинг: %s\n",
+		SearchExpertNoRatingText:           "Рейтинг: нет оценок\n",
 		SearchUserStateHeader:              "📝 <b>Состояние пользователя</b>\n",
 		SearchUserStateLineTemplate:        "State: <b>%s</b>\n",
 		SearchUserStateUpdatedLineTemplate: "Обновлено: %s\n",
@@ -175,6 +248,9 @@ func NewMessages(privacyPolicyURL, publicOfferURL string) *Messages {
 		ChatMessageHeaderTemplate:          "<b>%s</b> <i>%s</i>\n",
 		ChatTextLineTemplate:               "\t\t\t\t\t\t> %s",
 		ChatOtherLine:                      "\t\t\t\t\t\t> 🔡 <b>Другое</b>\n",
+		ChatRedactedLine:                   "\t\t\t\t\t\t> 🔒 <i>Данные скрыты от эксперта</i>\n",
+		SearchFraudHeader:                  "🚨 <b>Подозрительная активность</b>\n",
+		SearchFraudLineTemplate:            "<i>%s</i> %s: <code>%s</code> (%s)\n",
 		ChatQuoteBlockTemplate:             "<blockquote expandable>\n%s\n</blockquote>",
 		OrderStatusCreatedText:             "создан",
 		OrderStatusAcceptedText:            "принят",
@@ -192,6 +268,12 @@ func NewMessages(privacyPolicyURL, publicOfferURL string) *Messages {
 		MediaDocumentWithNameTemplate:      "📎 <b>Документ</b> : %s\n",
 		MediaDocumentLabel:                 "📎 <b>Документ</b>\n",
 		MediaVoiceLabel:                    "🎤 <b>Голосовое сообщение</b>\n",
+		ReviewRejectReasons: []string{
+			"Оскорбления или нецензурная лексика",
+			"Спам или реклама",
+			"Личные данные",
+			"Не относится к сделке",
+		},
 	}
 }
 
@@ -221,13 +303,174 @@ func (d *Dynamic) AssessorAcceptedOrder(orderID int, itemGame, itemType string)
 	)
 }
 
-func (d *Dynamic) AssessorAcceptedYourOrder(token string) string {
+func (d *Dynamic) AssessorAcceptedYourOrder(token, rating string) string {
+	expert := "Эксперт принял твою заявку!"
+	if rating != "" {
+		expert += fmt.Sprintf("\nРейтинг эксперта: %s", rating)
+	}
+	return fmt.Sprintf(
+		"%s\n\nТокен для обращения в поддержку:\n\n`%s` 🎉\n\nДальнейшее общение будет прямо здесь - удобно и быстро 😌",
+		expert,
+		token,
+	)
+}
+
+func (d *Dynamic) ExpertRating(average float64, count int, trend float64) string {
+	rating := fmt.Sprintf("⭐ %.1f (оценок: %d)", average, count)
+	switch {
+	case trend >= 0.1:
+		rating += " 📈"
+	case trend <= -0.1:
+		rating += " 📉"
+	}
+	return rating
+}
+
+func (d *Dynamic) UserBlockedBotWarning(graceHours int) string {
+	return fmt.Sprintf(
+		"⚠️ Клиент заблокировал бота - сообщения ему не доставляются.\n\nЗаявка приостановлена. Если клиент не вернётся в течение %d ч., она будет отменена автоматически.",
+		graceHours,
+	)
+}
+
+func (d *Dynamic) ResumeOrderPrompt(token string) string {
+	return fmt.Sprintf(
+		"С возвращением! 👋\n\nТвоя заявка всё ещё активна, эксперт на связи.\n\nТокен для обращения в поддержку:\n\n`%s`\n\nПродолжай общение прямо здесь 😌",
+		token,
+	)
+}
+
+func (d *Dynamic) FraudAlert(orderID int, token, sender, rules string) string {
 	return fmt.Sprintf(
-		"Эксперт принял твою заявку!\n\nТокен для обращения в поддержку:\n\n`%s` 🎉\n\nДальнейшее общение будет прямо здесь - удобно и быстро 😌",
+		"🚨 Подозрительное сообщение\n\nСделка #%d\nТокен: %s\nОтправитель: %s\nПравила: %s\n\n/search %s",
+		orderID,
+		token,
+		sender,
+		rules,
 		token,
 	)
 }
 
+func (d *Dynamic) FloodMutedText(minutes int) string {
+	return fmt.Sprintf(
+		"🔇 Отправка сообщений ограничена на %d мин. из-за флуда.",
+		minutes,
+	)
+}
+
+func (d *Dynamic) FloodMutedAlert(senderID int64, role string, minutes int) string {
+	return fmt.Sprintf(
+		"🔇 Флуд\n\nОтправитель: %d\nРоль: %s\nОграничение: %d мин.",
+		senderID,
+		role,
+		minutes,
+	)
+}
+
+func (d *Dynamic) ReviewModerationCard(
+	reviewID int,
+	orderID int,
+	token string,
+	rating int,
+	text string,
+) string {
+	return fmt.Sprintf(
+		"📝 Отзыв на модерации #%d\n\nСделка #%d\nТокен: %s\nОценка: %s\n\n%s",
+		reviewID,
+		orderID,
+		token,
+		strings.Repeat("⭐", rating),
+		text,
+	)
+}
+
+func (d *Dynamic) ReviewApprovedStatus(moderator string) string {
+	return fmt.Sprintf("\n\n✅ Опубликован · %s", moderator)
+}
+
+func (d *Dynamic) ReviewRejectedStatus(reason, moderator string) string {
+	return fmt.Sprintf("\n\n❌ Отклонён: %s · %s", reason, moderator)
+}
+
+func (d *Dynamic) ReviewRejectedUserText(reason string) string {
+	return fmt.Sprintf(
+		"Твой отзыв не прошёл модерацию.\nПричина: %s",
+		reason,
+	)
+}
+
+func (d *Dynamic) ReviewChannelPost(
+	rating int,
+	gameName string,
+	gameTypeName string,
+	text string,
+	author string,
+	date string,
+) string {
+	post := fmt.Sprintf(
+		"%s\n\n🎮 %s, %s",
+		strings.Repeat("⭐", rating),
+		gameName,
+		gameTypeName,
+	)
+	if text != "" {
+		post += fmt.Sprintf("\n\n«%s»", text)
+	}
+	return post + fmt.Sprintf("\n\n— %s, %s", author, date)
+}
+
+func (d *Dynamic) ReviewsSummary(
+	gameName string,
+	average float64,
+	count int,
+	byRating [5]int,
+) string {
+	title := "⭐️ Отзывы клиентов"
+	if gameName != "" {
+		title += " · " + gameName
+	}
+	if count == 0 {
+		return title
+	}
+
+	summary := fmt.Sprintf(
+		"%s\n\nСредняя оценка: %.1f из 5\nВсего отзывов: %d\n",
+		title,
+		average,
+		count,
+	)
+	for rating := len(byRating); rating >= 1; rating-- {
+		summary += fmt.Sprintf("\n%d⭐ — %d", rating, byRating[rating-1])
+	}
+	return summary
+}
+
+func (d *Dynamic) ReviewsListItem(
+	rating int,
+	gameName string,
+	gameTypeName string,
+	text string,
+	author string,
+	date string,
+) string {
+	item := fmt.Sprintf(
+		"%s %s, %s\n%s · %s",
+		strings.Repeat("⭐", rating),
+		gameName,
+		gameTypeName,
+		author,
+		date,
+	)
+	if text != "" {
+		item += fmt.Sprintf("\n«%s»", text)
+	}
+	return item
+}
+
+func (d *Dynamic) ReviewsPageFooter(page, pages int) string {
+	return fmt.Sprintf("Страница %d из %d", page, pages)
+}
+
 func (d *Dynamic) TitleOrderTopic(orderID int, itemGame, itemType string) string {
 	return fmt.Sprintf("💼 Сделка #%d - (%s, %s)", orderID, itemGame, itemType)
 }